@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/utils"
+)
+
+// analyzeMap prints the regions of the configured map, the points reachable
+// from the start point and the choke points, e.g. "analyze -json". The
+// obstacles are the ones the rover drives on, terrain and moving obstacles
+// included.
+func analyzeMap(args []string, components *components, grid models.GridDto, start models.PointDto) error {
+	flags := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	asJson := flags.Bool("json", false, "Prints the report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	analysis, err := components.analysis.Analyze(start)
+	if err != nil {
+		return err
+	}
+
+	if *asJson {
+		content, err := json.MarshalIndent(analysis, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(content))
+		return nil
+	}
+
+	fmt.Printf("Start point: %s\n", utils.PointToString(analysis.Start))
+	fmt.Printf("Reachable points: %d of %d free points\n", analysis.ReachablePoints, analysis.FreePoints)
+	for _, region := range analysis.Regions {
+		reachable := "unreachable"
+		if region.Reachable {
+			reachable = "reachable"
+		}
+		fmt.Printf("Region %d: %d points, %s, choke points: %s\n", region.Id, len(region.Points), reachable, pointsToString(region.ChokePoints))
+	}
+	printAnalysisMap(grid, analysis)
+
+	return nil
+}
+
+// printAnalysisMap prints the map, north up: 'S' start point, '.' reachable,
+// '+' choke point, '?' unreachable and '#' obstacle
+func printAnalysisMap(grid models.GridDto, analysis models.MapAnalysisDto) {
+	marks := map[models.PointDto]string{}
+	for _, region := range analysis.Regions {
+		mark := "?"
+		if region.Reachable {
+			mark = "."
+		}
+		for _, point := range region.Points {
+			marks[point] = mark
+		}
+		for _, point := range region.ChokePoints {
+			marks[point] = "+"
+		}
+	}
+	marks[analysis.Start] = "S"
+
+	fmt.Println()
+	for y := grid.YPointMax; y >= 0; y-- {
+		fmt.Print("\t")
+		for x := 0; x <= grid.XPointMax; x++ {
+			mark, ok := marks[models.PointDto{XPoint: x, YPoint: y}]
+			if !ok {
+				mark = "#"
+			}
+			fmt.Print(mark)
+		}
+		fmt.Println()
+	}
+}
+
+func pointsToString(points []models.PointDto) string {
+	if len(points) == 0 {
+		return "none"
+	}
+
+	strs := make([]string, len(points))
+	for i, point := range points {
+		strs[i] = utils.PointToString(point)
+	}
+
+	return strings.Join(strs, " ")
+}
@@ -0,0 +1,98 @@
+// Package api exposes the rover domains over the network
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mars-rover-go/domains"
+	"github.com/mars-rover-go/models"
+)
+
+// RoverController executes the network clients requests on the rover
+type RoverController struct {
+	rover          domains.IRoverDomain
+	envelopeDomain domains.IEnvelopeDomain
+	navigator      domains.INavigatorDomain
+}
+
+// NewRoverController returns a rover controller. When envelopeDomain is not
+// nil the command frames must carry a signed envelope. When navigator is not
+// nil the rover returns home and retreats on request, and the commands are
+// executed with its auto retreat.
+func NewRoverController(rover domains.IRoverDomain, envelopeDomain domains.IEnvelopeDomain, navigator domains.INavigatorDomain) *RoverController {
+	return &RoverController{
+		rover:          rover,
+		envelopeDomain: envelopeDomain,
+		navigator:      navigator,
+	}
+}
+
+// Execute executes the commands of the frame on the rover, stopping when
+// the context is done
+func (c *RoverController) Execute(ctx context.Context, commandFrame models.CommandFrameDto) (models.LocationDto, error) {
+	commands, err := verifyCommands(c.envelopeDomain, commandFrame)
+	if err != nil {
+		return c.rover.Location(), err
+	}
+	if c.navigator != nil {
+		return c.navigator.ExecuteTimedCommands(ctx, commands, models.CommandDurationDto{})
+	}
+
+	return c.rover.ExecuteCommandsContext(ctx, commands)
+}
+
+// ReturnHome drives the rover back to its starting location
+func (c *RoverController) ReturnHome(ctx context.Context) (models.LocationDto, error) {
+	if c.navigator == nil {
+		return c.rover.Location(), fmt.Errorf("navigation not available")
+	}
+
+	return c.navigator.ReturnHome(ctx, models.CommandDurationDto{})
+}
+
+// Retreat drives the rover to the nearest safe zone
+func (c *RoverController) Retreat(ctx context.Context) (models.LocationDto, error) {
+	if c.navigator == nil {
+		return c.rover.Location(), fmt.Errorf("navigation not available")
+	}
+
+	return c.navigator.Retreat(ctx, models.CommandDurationDto{})
+}
+
+// Location returns the rover location
+func (c *RoverController) Location() models.LocationDto {
+	return c.rover.Location()
+}
+
+// Reset lands the rover again on the location
+func (c *RoverController) Reset(location models.LocationDto) (models.LocationDto, error) {
+	err := c.rover.Reset(location)
+
+	return c.rover.Location(), err
+}
+
+// Plan returns the events the commands would produce, without moving the rover
+func (c *RoverController) Plan(commands []string) (models.PlanDto, error) {
+	return c.rover.Plan(commands)
+}
+
+// verifyCommands returns the commands of the frame to execute. When
+// envelopeDomain is not nil only the commands of a valid signed envelope are accepted.
+func verifyCommands(envelopeDomain domains.IEnvelopeDomain, commandFrame models.CommandFrameDto) ([]string, error) {
+	if envelopeDomain == nil {
+		if commandFrame.Envelope != nil {
+			return commandFrame.Envelope.Commands, nil
+		}
+		return commandFrame.Commands, nil
+	}
+
+	if commandFrame.Envelope == nil {
+		return nil, fmt.Errorf("signed envelope required")
+	}
+	if err := envelopeDomain.Verify(*commandFrame.Envelope); err != nil {
+		return nil, err
+	}
+
+	return commandFrame.Envelope.Commands, nil
+}
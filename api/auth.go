@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/mars-rover-go/models"
+)
+
+var (
+	ErrUnauthenticated = errors.New("token missing or unknown")
+	ErrForbidden       = errors.New("operation not allowed")
+)
+
+var rolePermissions = map[models.Role][]models.Operation{
+	models.RoleViewer: {models.OperationReadState},
+	models.RoleDriver: {models.OperationReadState, models.OperationSendCommands},
+	models.RoleAdmin:  {models.OperationReadState, models.OperationSendCommands, models.OperationEditMap, models.OperationResetRover},
+}
+
+// Authorizer checks the operations allowed to a token by its role.
+// A nil Authorizer allows every operation.
+type Authorizer struct {
+	roles map[string]models.Role
+}
+
+func NewAuthorizer(tokens models.TokensDto) (*Authorizer, error) {
+	roles := map[string]models.Role{}
+	for _, t := range tokens.Tokens {
+		if t.Token == "" {
+			return nil, fmt.Errorf("token of '%s' empty", t.Name)
+		}
+		if _, ok := rolePermissions[t.Role]; !ok {
+			return nil, fmt.Errorf("role '%s' of '%s' unknown", t.Role, t.Name)
+		}
+		roles[t.Token] = t.Role
+	}
+
+	return &Authorizer{roles}, nil
+}
+
+// LoadAuthorizer reads the tokens from a JSON file
+func LoadAuthorizer(path string) (*Authorizer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens models.TokensDto
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, err
+	}
+
+	return NewAuthorizer(tokens)
+}
+
+// Authorize checks the token role allows the operation
+func (a *Authorizer) Authorize(token string, operation models.Operation) error {
+	if a == nil {
+		return nil
+	}
+
+	role, ok := a.roles[token]
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	for _, allowed := range rolePermissions[role] {
+		if allowed == operation {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s cannot %s", ErrForbidden, role, operation)
+}
+
+// Middleware rejects the requests whose token does not allow the operation
+func (a *Authorizer) Middleware(operation models.Operation, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := a.Authorize(requestToken(r), operation); err != nil {
+			writeAuthError(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestToken returns the bearer token of the request, or the token query
+// parameter for the clients unable to set headers such as browser websockets
+func requestToken(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimPrefix(header, "Bearer ")
+	}
+
+	return r.URL.Query().Get("token")
+}
+
+func writeAuthError(w http.ResponseWriter, err error) {
+	status := http.StatusForbidden
+	if errors.Is(err, ErrUnauthenticated) {
+		status = http.StatusUnauthorized
+	}
+
+	http.Error(w, err.Error(), status)
+}
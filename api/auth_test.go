@@ -0,0 +1,277 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mars-rover-go/domains"
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/utils"
+)
+
+func TestAuthorizer_Authorize(t *testing.T) {
+	type args struct {
+		token     string
+		operation models.Operation
+	}
+
+	authorizer := newAuthorizerMocked(t)
+
+	tests := []struct {
+		name    string
+		a       *Authorizer
+		args    args
+		wantErr error
+	}{
+		{
+			name: "No authorizer",
+			a:    nil,
+			args: args{
+				token:     "",
+				operation: models.OperationEditMap,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Token unknown",
+			a:    authorizer,
+			args: args{
+				token:     "unknown",
+				operation: models.OperationReadState,
+			},
+			wantErr: ErrUnauthenticated,
+		},
+		{
+			name: "Viewer reads state",
+			a:    authorizer,
+			args: args{
+				token:     "viewer-token",
+				operation: models.OperationReadState,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Viewer sends commands",
+			a:    authorizer,
+			args: args{
+				token:     "viewer-token",
+				operation: models.OperationSendCommands,
+			},
+			wantErr: ErrForbidden,
+		},
+		{
+			name: "Driver edits map",
+			a:    authorizer,
+			args: args{
+				token:     "driver-token",
+				operation: models.OperationEditMap,
+			},
+			wantErr: ErrForbidden,
+		},
+		{
+			name: "Admin edits map",
+			a:    authorizer,
+			args: args{
+				token:     "admin-token",
+				operation: models.OperationEditMap,
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.a.Authorize(tt.args.token, tt.args.operation); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Authorizer.Authorize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewHttpHandler(t *testing.T) {
+	eventBus := domains.NewEventBusDomain()
+	defer eventBus.Close()
+
+	obstacles, _ := newObstacleControllerMocked(t, "", "")
+	rover := newRoverDomainMocked(t, eventBus)
+	handler := NewHttpHandler(NewRoverController(rover, nil, newNavigatorDomainMocked(rover)), obstacles, "", newAuthorizerMocked(t))
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "State without token",
+			method:     http.MethodGet,
+			path:       "/api/state",
+			token:      "",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "State read by viewer",
+			method:     http.MethodGet,
+			path:       "/api/state",
+			token:      "viewer-token",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Commands sent by viewer",
+			method:     http.MethodPost,
+			path:       "/api/commands",
+			token:      "viewer-token",
+			body:       `{"Commands": ["f"]}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Commands sent by driver",
+			method:     http.MethodPost,
+			path:       "/api/commands",
+			token:      "driver-token",
+			body:       `{"Commands": ["f"]}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Home requested by viewer",
+			method:     http.MethodPost,
+			path:       "/api/home",
+			token:      "viewer-token",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Home requested by driver",
+			method:     http.MethodPost,
+			path:       "/api/home",
+			token:      "driver-token",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Retreat requested by driver",
+			method:     http.MethodPost,
+			path:       "/api/retreat",
+			token:      "driver-token",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Configuration edited by driver",
+			method:     http.MethodPut,
+			path:       "/api/configuration",
+			token:      "driver-token",
+			body:       `{}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Obstacles read by viewer",
+			method:     http.MethodGet,
+			path:       "/api/obstacles",
+			token:      "viewer-token",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Obstacles added by driver",
+			method:     http.MethodPost,
+			path:       "/api/obstacles",
+			token:      "driver-token",
+			body:       `[{"Point": {"XPoint": 3, "YPoint": 3}}]`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Obstacles added by admin",
+			method:     http.MethodPost,
+			path:       "/api/obstacles",
+			token:      "admin-token",
+			body:       `[{"Point": {"XPoint": 3, "YPoint": 3}}]`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Obstacles added out the grid by admin",
+			method:     http.MethodPost,
+			path:       "/api/obstacles",
+			token:      "admin-token",
+			body:       `[{"Point": {"XPoint": 30, "YPoint": 3}}]`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Obstacles removed by admin",
+			method:     http.MethodDelete,
+			path:       "/api/obstacles",
+			token:      "admin-token",
+			body:       `[{"Point": {"XPoint": 3, "YPoint": 3}}]`,
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			r.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("HttpHandler %s %s status = %v, want %v", tt.method, tt.path, w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNewHttpHandler_Configuration(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	err := utils.SaveConfiguration(configPath, models.ConfigurationDto{
+		Grid:     models.GridDto{XPointMax: 10, YPointMax: 10},
+		Security: models.SecurityDto{Key: "shared-secret", MaxAgeSeconds: 30},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewHttpHandler(NewRoverController(newRoverDomainMocked(t, nil), nil, nil), nil, configPath, newAuthorizerMocked(t))
+
+	for _, tt := range []struct {
+		method string
+		token  string
+		body   string
+	}{
+		{method: http.MethodGet, token: "viewer-token"},
+		{method: http.MethodPut, token: "admin-token", body: `{"Grid": {"XPointMax": 5, "YPointMax": 5}}`},
+	} {
+		r := httptest.NewRequest(tt.method, "/api/configuration", strings.NewReader(tt.body))
+		r.Header.Set("Authorization", "Bearer "+tt.token)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK || strings.Contains(w.Body.String(), "shared-secret") {
+			t.Errorf("HttpHandler %s /api/configuration = %v %s, want the configuration without the key", tt.method, w.Code, w.Body.String())
+		}
+	}
+
+	config, err := utils.LoadConfiguration(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Security.Key != "shared-secret" {
+		t.Errorf("HttpHandler PUT /api/configuration saved key %q, want the key kept", config.Security.Key)
+	}
+}
+
+func newAuthorizerMocked(t *testing.T) *Authorizer {
+	authorizer, err := NewAuthorizer(models.TokensDto{
+		Tokens: []models.TokenDto{
+			{Token: "viewer-token", Role: models.RoleViewer, Name: "intern"},
+			{Token: "driver-token", Role: models.RoleDriver, Name: "driver"},
+			{Token: "admin-token", Role: models.RoleAdmin, Name: "admin"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return authorizer
+}
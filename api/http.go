@@ -0,0 +1,213 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/utils"
+)
+
+// NewHttpHandler returns the REST endpoints of the rover:
+//   - GET /api/state: rover location, for viewers
+//   - POST /api/commands: executes a command frame, for drivers
+//   - POST /api/home: drives the rover back to its starting location, for drivers
+//   - POST /api/retreat: drives the rover to the nearest safe zone, for drivers
+//   - GET /api/configuration: grid and obstacles, the rest of the
+//     configuration is not served, for viewers
+//   - PUT /api/configuration: saves the grid and obstacles, for admins.
+//     The configuration is applied the next time the rover starts.
+//   - GET /api/obstacles: current obstacles, for viewers
+//   - POST /api/obstacles: adds the obstacles of the body, a list formatted as
+//     the configuration obstacles, for admins
+//   - DELETE /api/obstacles: removes the obstacles of the body, for admins
+//   - POST /api/obstacles/save: saves the current obstacles to the ASCII map or the configuration, for admins
+//
+// The obstacles endpoints are served when obstacles is not nil.
+func NewHttpHandler(controller *RoverController, obstacles *ObstacleController, configPath string, authorizer *Authorizer) http.Handler {
+	configuration := &configurationHandler{path: configPath, authorizer: authorizer}
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/state", authorizer.Middleware(models.OperationReadState, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, models.TelemetryFrameDto{Type: models.TelemetryFrameTypeState, Location: controller.Location()})
+	})))
+	mux.Handle("/api/commands", authorizer.Middleware(models.OperationSendCommands, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var commandFrame models.CommandFrameDto
+		if err := json.NewDecoder(r.Body).Decode(&commandFrame); err != nil {
+			http.Error(w, fmt.Sprintf("invalid command frame: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		location, err := controller.Execute(r.Context(), commandFrame)
+		result := models.TelemetryFrameDto{Type: models.TelemetryFrameTypeResult, Location: location}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		writeJSON(w, http.StatusOK, result)
+	})))
+	mux.Handle("/api/home", authorizer.Middleware(models.OperationSendCommands, navigationHandler(controller.ReturnHome)))
+	mux.Handle("/api/retreat", authorizer.Middleware(models.OperationSendCommands, navigationHandler(controller.Retreat)))
+	mux.Handle("/api/configuration", configuration)
+
+	if obstacles != nil {
+		mux.Handle("/api/obstacles", &obstaclesHandler{obstacles: obstacles, authorizer: authorizer})
+		mux.Handle("/api/obstacles/save", authorizer.Middleware(models.OperationEditMap, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			if err := obstacles.Save(); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			writeJSON(w, http.StatusOK, models.ObstaclesDto{Obstacles: obstacles.Obstacles()})
+		})))
+	}
+
+	return mux
+}
+
+// navigationHandler answers with the rover location after the navigation
+func navigationHandler(navigate func(ctx context.Context) (models.LocationDto, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		location, err := navigate(r.Context())
+		result := models.TelemetryFrameDto{Type: models.TelemetryFrameTypeResult, Location: location}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+type obstaclesHandler struct {
+	obstacles  *ObstacleController
+	authorizer *Authorizer
+}
+
+func (h *obstaclesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	operation := models.OperationReadState
+	if r.Method != http.MethodGet {
+		operation = models.OperationEditMap
+	}
+	if err := h.authorizer.Authorize(requestToken(r), operation); err != nil {
+		writeAuthError(w, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, models.ObstaclesDto{Obstacles: h.obstacles.Obstacles()})
+	case http.MethodPost:
+		obstacles, err := h.obstacles.Import(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusOK, models.ObstaclesDto{Obstacles: obstacles})
+	case http.MethodDelete:
+		var removed []models.ObstacleDto
+		if err := json.NewDecoder(r.Body).Decode(&removed); err != nil {
+			http.Error(w, fmt.Sprintf("invalid obstacles: %v", err), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusOK, models.ObstaclesDto{Obstacles: h.obstacles.Remove(removed)})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+type configurationHandler struct {
+	path       string
+	authorizer *Authorizer
+}
+
+func (h *configurationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	operation := models.OperationReadState
+	if r.Method != http.MethodGet {
+		operation = models.OperationEditMap
+	}
+	if err := h.authorizer.Authorize(requestToken(r), operation); err != nil {
+		writeAuthError(w, err)
+		return
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	config, err := utils.LoadConfiguration(h.path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, mapOf(*config))
+	case http.MethodPut:
+		var edited models.ConfigurationDto
+		if err := json.NewDecoder(r.Body).Decode(&edited); err != nil {
+			http.Error(w, fmt.Sprintf("invalid configuration: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := validateMap(edited); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Only the grid and obstacles are editable, the rest of the configuration is kept
+		config.Grid = edited.Grid
+		config.Obstacle = edited.Obstacle
+		if err := utils.SaveConfiguration(h.path, *config); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, mapOf(*config))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// mapOf returns the grid and obstacles of the configuration, the only part
+// served. The rest, such as the security key, never leaves the rover.
+func mapOf(config models.ConfigurationDto) models.ConfigurationDto {
+	return models.ConfigurationDto{Grid: config.Grid, Obstacle: config.Obstacle}
+}
+
+func validateMap(config models.ConfigurationDto) error {
+	if config.Grid.XPointMax < 0 || config.Grid.YPointMax < 0 {
+		return fmt.Errorf("grid size invalid")
+	}
+
+	for _, o := range config.Obstacle {
+		if o.Point.XPoint < 0 || o.Point.XPoint > config.Grid.XPointMax ||
+			o.Point.YPoint < 0 || o.Point.YPoint > config.Grid.YPointMax {
+			return fmt.Errorf("obstacle %s out the grid", utils.PointToString(o.Point))
+		}
+	}
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mars-rover-go/domains"
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/utils"
+)
+
+// configMu serializes the edits of the configuration file
+var configMu sync.Mutex
+
+// ObstacleController edits the obstacles while the rover moves
+type ObstacleController struct {
+	mu             sync.Mutex
+	obstacleDomain domains.IObstacleMapDomain
+	gridDomain     domains.IGridDomain
+	rover          domains.IRoverDomain
+	configPath     string
+	mapPath        string
+}
+
+// NewObstacleController returns an obstacle controller saving the obstacles
+// to the ASCII map file the grid is loaded from, or to the configuration file
+// when mapPath is empty
+func NewObstacleController(obstacleDomain domains.IObstacleMapDomain, gridDomain domains.IGridDomain, rover domains.IRoverDomain, configPath string, mapPath string) *ObstacleController {
+	return &ObstacleController{
+		obstacleDomain: obstacleDomain,
+		gridDomain:     gridDomain,
+		rover:          rover,
+		configPath:     configPath,
+		mapPath:        mapPath,
+	}
+}
+
+// Obstacles returns the obstacles
+func (c *ObstacleController) Obstacles() []models.ObstacleDto {
+	return c.obstacleDomain.Obstacles()
+}
+
+// Add adds the obstacles. None is added when one is out the grid or under the rover.
+func (c *ObstacleController) Add(obstacles []models.ObstacleDto) ([]models.ObstacleDto, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	location := c.rover.Location()
+	for _, o := range obstacles {
+		if !c.gridDomain.IsPointInGrid(o.Point) {
+			return c.obstacleDomain.Obstacles(), fmt.Errorf("obstacle %s out the grid", utils.PointToString(o.Point))
+		}
+		if o.Point == location.Point {
+			return c.obstacleDomain.Obstacles(), fmt.Errorf("obstacle %s under the rover", utils.PointToString(o.Point))
+		}
+	}
+
+	c.obstacleDomain.Add(obstacles...)
+
+	return c.obstacleDomain.Obstacles(), nil
+}
+
+// Remove removes the obstacles
+func (c *ObstacleController) Remove(obstacles []models.ObstacleDto) []models.ObstacleDto {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.obstacleDomain.Remove(obstacles...)
+
+	return c.obstacleDomain.Obstacles()
+}
+
+// Import adds the obstacles of a JSON file, a list formatted as the
+// obstacles of the configuration
+func (c *ObstacleController) Import(r io.Reader) ([]models.ObstacleDto, error) {
+	var obstacles []models.ObstacleDto
+	if err := json.NewDecoder(r).Decode(&obstacles); err != nil {
+		return c.obstacleDomain.Obstacles(), fmt.Errorf("invalid obstacles: %v", err)
+	}
+
+	return c.Add(obstacles)
+}
+
+// Save writes the obstacles to the ASCII map or the configuration, they are
+// loaded the next time the rover starts
+func (c *ObstacleController) Save() error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	if c.mapPath != "" {
+		return c.saveAsciiMap()
+	}
+
+	config, err := utils.LoadConfiguration(c.configPath)
+	if err != nil {
+		return err
+	}
+
+	config.Obstacle = c.obstacleDomain.Obstacles()
+
+	return utils.SaveConfiguration(c.configPath, *config)
+}
+
+// saveAsciiMap writes the obstacles to the ASCII map, keeping its grid and
+// rover start
+func (c *ObstacleController) saveAsciiMap() error {
+	file, err := os.Open(c.mapPath)
+	if err != nil {
+		return err
+	}
+	m, err := utils.ParseAsciiMap(file)
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	m.Obstacles = c.obstacleDomain.Obstacles()
+	if m.Start != nil {
+		for _, o := range m.Obstacles {
+			if o.Point == m.Start.Point {
+				return fmt.Errorf("obstacle %s on the map rover start", utils.PointToString(o.Point))
+			}
+		}
+	}
+
+	var text bytes.Buffer
+	if err := utils.WriteAsciiMap(&text, m); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.mapPath, text.Bytes(), 0644)
+}
+
+// ExecuteCommand executes an obstacle edit written as text:
+//   - ADD <x> <y> [<x> <y>...]: adds the obstacles
+//   - REMOVE <x> <y> [<x> <y>...]: removes the obstacles
+//   - SAVE: writes the obstacles to the ASCII map or the configuration
+//   - LIST: returns the obstacles
+func (c *ObstacleController) ExecuteCommand(command string) ([]models.ObstacleDto, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return c.Obstacles(), fmt.Errorf("usage: ADD|REMOVE <x> <y>..., SAVE or LIST")
+	}
+
+	switch strings.ToUpper(fields[0]) {
+	case "ADD":
+		obstacles, err := parseObstacles(fields[1:])
+		if err != nil {
+			return c.Obstacles(), err
+		}
+		return c.Add(obstacles)
+	case "REMOVE":
+		obstacles, err := parseObstacles(fields[1:])
+		if err != nil {
+			return c.Obstacles(), err
+		}
+		return c.Remove(obstacles), nil
+	case "SAVE":
+		return c.Obstacles(), c.Save()
+	case "LIST":
+		return c.Obstacles(), nil
+	}
+
+	return c.Obstacles(), fmt.Errorf("obstacle command '%s' unknown", fields[0])
+}
+
+func parseObstacles(fields []string) ([]models.ObstacleDto, error) {
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return nil, fmt.Errorf("points expected as <x> <y> pairs")
+	}
+
+	obstacles := []models.ObstacleDto{}
+	for i := 0; i < len(fields); i += 2 {
+		x, errX := strconv.Atoi(fields[i])
+		y, errY := strconv.Atoi(fields[i+1])
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("invalid point '%s %s'", fields[i], fields[i+1])
+		}
+		obstacles = append(obstacles, models.ObstacleDto{Point: models.PointDto{XPoint: x, YPoint: y}})
+	}
+
+	return obstacles, nil
+}
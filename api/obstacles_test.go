@@ -0,0 +1,200 @@
+package api
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mars-rover-go/domains"
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/utils"
+)
+
+func TestObstacleController_ExecuteCommand(t *testing.T) {
+	c, rover := newObstacleControllerMocked(t, "", "")
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "List",
+			command: "LIST",
+			want:    "(2,6)",
+		},
+		{
+			name:    "Add",
+			command: "add 1 3 4 4",
+			want:    "(2,6) (1,3) (4,4)",
+		},
+		{
+			name:    "Add existing obstacle",
+			command: "ADD 2 6",
+			want:    "(2,6) (1,3) (4,4)",
+		},
+		{
+			name:    "Add out the grid",
+			command: "ADD 5 5 11 5",
+			want:    "(2,6) (1,3) (4,4)",
+			wantErr: true,
+		},
+		{
+			name:    "Add under the rover",
+			command: "ADD 1 1",
+			want:    "(2,6) (1,3) (4,4)",
+			wantErr: true,
+		},
+		{
+			name:    "Add odd coordinates",
+			command: "ADD 5",
+			want:    "(2,6) (1,3) (4,4)",
+			wantErr: true,
+		},
+		{
+			name:    "Remove",
+			command: "REMOVE 2 6 7 7",
+			want:    "(1,3) (4,4)",
+		},
+		{
+			name:    "Unknown command",
+			command: "MOVE 1 1",
+			want:    "(1,3) (4,4)",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.ExecuteCommand(tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ObstacleController.ExecuteCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if utils.ObstaclesToString(got) != tt.want {
+				t.Errorf("ObstacleController.ExecuteCommand() = %s, want %s", utils.ObstaclesToString(got), tt.want)
+			}
+		})
+	}
+
+	// The rover is blocked by the obstacle added while it is landed
+	if _, err := rover.ExecuteCommands([]string{"f", "f"}); err == nil {
+		t.Errorf("RoverDomain.ExecuteCommands() error = nil, want obstacle detected at (1,3)")
+	}
+}
+
+func TestObstacleController_ImportSave(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	err := utils.SaveConfiguration(configPath, models.ConfigurationDto{
+		Grid:             models.GridDto{XPointMax: 10, YPointMax: 10},
+		Obstacle:         []models.ObstacleDto{{Point: models.PointDto{XPoint: 2, YPoint: 6}}},
+		SnapshotInterval: 50,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := newObstacleControllerMocked(t, configPath, "")
+
+	if _, err := c.Import(strings.NewReader(`[{"Point": {"XPoint": 3, "YPoint": 3}}, {"Point": {"XPoint": 4, "YPoint": 4}}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Import(strings.NewReader(`{"Point": {}}`)); err == nil {
+		t.Errorf("ObstacleController.Import() error = nil, want invalid obstacles")
+	}
+	c.Remove([]models.ObstacleDto{{Point: models.PointDto{XPoint: 2, YPoint: 6}}})
+
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := utils.LoadConfiguration(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.ObstacleDto{{Point: models.PointDto{XPoint: 3, YPoint: 3}}, {Point: models.PointDto{XPoint: 4, YPoint: 4}}}
+	if !reflect.DeepEqual(config.Obstacle, want) {
+		t.Errorf("ObstacleController.Save() saved %v, want %v", config.Obstacle, want)
+	}
+	if config.SnapshotInterval != 50 {
+		t.Errorf("ObstacleController.Save() snapshot interval = %d, want the configuration kept", config.SnapshotInterval)
+	}
+}
+
+func TestObstacleController_SaveAsciiMap(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	config := models.ConfigurationDto{Grid: models.GridDto{XPointMax: 20, YPointMax: 20}, SnapshotInterval: 50}
+	if err := utils.SaveConfiguration(configPath, config); err != nil {
+		t.Fatal(err)
+	}
+	mapPath := filepath.Join(dir, "mars.txt")
+	m := models.MapDto{
+		Grid:      models.GridDto{XPointMax: 10, YPointMax: 10},
+		Obstacles: []models.ObstacleDto{{Point: models.PointDto{XPoint: 2, YPoint: 6}}},
+		Start:     &models.LocationDto{Point: models.PointDto{XPoint: 0, YPoint: 0}, Direction: models.DirectionEast},
+	}
+	var text bytes.Buffer
+	if err := utils.WriteAsciiMap(&text, m); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(mapPath, text.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := newObstacleControllerMocked(t, configPath, mapPath)
+	if _, err := c.ExecuteCommand("ADD 10 10"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ExecuteCommand("SAVE"); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(mapPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	got, err := utils.ParseAsciiMap(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Obstacles = append(m.Obstacles, models.ObstacleDto{Point: models.PointDto{XPoint: 10, YPoint: 10}})
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("ObstacleController.Save() saved %+v, want %+v", got, m)
+	}
+	if saved, err := utils.LoadConfiguration(configPath); err != nil || !reflect.DeepEqual(*saved, config) {
+		t.Errorf("ObstacleController.Save() configuration = %+v, %v, want it unchanged", saved, err)
+	}
+
+	// The map start cannot hold an obstacle
+	if _, err := c.ExecuteCommand("ADD 0 0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Save(); err == nil {
+		t.Errorf("ObstacleController.Save() error = nil, want obstacle on the map rover start")
+	}
+}
+
+func newObstacleControllerMocked(t *testing.T, configPath string, mapPath string) (*ObstacleController, domains.IRoverDomain) {
+	gridDomain := domains.NewGridDomain(models.GridDto{XPointMax: 10, YPointMax: 10})
+	obstacleDomain := domains.NewObstacleDomain([]models.ObstacleDto{{Point: models.PointDto{XPoint: 2, YPoint: 6}}})
+
+	rover, err := domains.NewRoverDomain(
+		1,
+		models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorth},
+		gridDomain,
+		obstacleDomain,
+		nil,
+		nil,
+		domains.NewRealClockDomain(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewObstacleController(obstacleDomain, gridDomain, rover, configPath, mapPath), rover
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mars-rover-go/domains"
+	"github.com/mars-rover-go/models"
+)
+
+// RpcPathPrefix is the path prefix of the RoverService methods
+// described in proto/rover/v1/rover.proto
+const RpcPathPrefix = "/rover.v1.RoverService/"
+
+// NewRpcHandler serves the RoverService methods over HTTP, each method is a
+// POST with a JSON body. StreamEvents answers with one JSON event per line.
+// The obstacles methods are served when obstacles is not nil.
+func NewRpcHandler(roverId int, controller *RoverController, obstacles *ObstacleController, eventStore domains.IEventStoreDomain, eventBus domains.IEventBusDomain, authorizer *Authorizer) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.Handle(RpcPathPrefix+"ExecuteCommands", rpcMethod(authorizer, models.OperationSendCommands, func(w http.ResponseWriter, r *http.Request) {
+		var request models.CommandFrameDto
+		if !decodeRequest(w, r, &request) {
+			return
+		}
+
+		location, err := controller.Execute(r.Context(), request)
+		response := models.CommandResultDto{Location: location}
+		if err != nil {
+			response.Error = err.Error()
+		}
+		writeJSON(w, http.StatusOK, response)
+	}))
+
+	mux.Handle(RpcPathPrefix+"ReturnHome", rpcMethod(authorizer, models.OperationSendCommands, rpcNavigation(controller.ReturnHome)))
+	mux.Handle(RpcPathPrefix+"Retreat", rpcMethod(authorizer, models.OperationSendCommands, rpcNavigation(controller.Retreat)))
+
+	mux.Handle(RpcPathPrefix+"GetState", rpcMethod(authorizer, models.OperationReadState, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, models.StateDto{Location: controller.Location()})
+	}))
+
+	mux.Handle(RpcPathPrefix+"Plan", rpcMethod(authorizer, models.OperationReadState, func(w http.ResponseWriter, r *http.Request) {
+		var request models.PlanRequestDto
+		if !decodeRequest(w, r, &request) {
+			return
+		}
+
+		plan, err := controller.Plan(request.Commands)
+		if err != nil {
+			plan.Error = err.Error()
+		}
+		writeJSON(w, http.StatusOK, plan)
+	}))
+
+	mux.Handle(RpcPathPrefix+"StreamEvents", rpcMethod(authorizer, models.OperationReadState, func(w http.ResponseWriter, r *http.Request) {
+		var request models.StreamEventsRequestDto
+		if !decodeRequest(w, r, &request) {
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming not supported", http.StatusInternalServerError)
+			return
+		}
+
+		// The events are read from the store, where the rover appends them
+		// before publishing them, the bus only waking the stream up. So no
+		// event is lost when the stream is slower than the rover.
+		notify := make(chan struct{}, 1)
+		subscription := eventBus.Subscribe(domains.SubscriberFunc(func(event models.EventDto) {
+			if event.RoverId != roverId {
+				return
+			}
+			select {
+			case notify <- struct{}{}:
+			default:
+			}
+		}), TelemetryBufferSize, models.DeliveryPolicyDrop)
+		defer subscription.Unsubscribe()
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		encoder := json.NewEncoder(w)
+
+		lastSequence := request.FromSequence
+		for {
+			for _, event := range eventStore.Events(roverId, lastSequence) {
+				if encoder.Encode(event) != nil {
+					return
+				}
+				lastSequence = event.Sequence
+			}
+			flusher.Flush()
+
+			select {
+			case <-notify:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}))
+
+	if obstacles != nil {
+		handleObstacleMethods(mux, obstacles, authorizer)
+	}
+
+	return mux
+}
+
+func handleObstacleMethods(mux *http.ServeMux, obstacles *ObstacleController, authorizer *Authorizer) {
+	mux.Handle(RpcPathPrefix+"ListObstacles", rpcMethod(authorizer, models.OperationReadState, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, models.ObstaclesDto{Obstacles: obstacles.Obstacles()})
+	}))
+
+	mux.Handle(RpcPathPrefix+"AddObstacles", rpcMethod(authorizer, models.OperationEditMap, func(w http.ResponseWriter, r *http.Request) {
+		var request models.ObstaclesDto
+		if !decodeRequest(w, r, &request) {
+			return
+		}
+
+		edited, err := obstacles.Add(request.Obstacles)
+		response := models.ObstaclesDto{Obstacles: edited}
+		if err != nil {
+			response.Error = err.Error()
+		}
+		writeJSON(w, http.StatusOK, response)
+	}))
+
+	mux.Handle(RpcPathPrefix+"RemoveObstacles", rpcMethod(authorizer, models.OperationEditMap, func(w http.ResponseWriter, r *http.Request) {
+		var request models.ObstaclesDto
+		if !decodeRequest(w, r, &request) {
+			return
+		}
+
+		writeJSON(w, http.StatusOK, models.ObstaclesDto{Obstacles: obstacles.Remove(request.Obstacles)})
+	}))
+
+	mux.Handle(RpcPathPrefix+"SaveObstacles", rpcMethod(authorizer, models.OperationEditMap, func(w http.ResponseWriter, r *http.Request) {
+		response := models.ObstaclesDto{Obstacles: obstacles.Obstacles()}
+		if err := obstacles.Save(); err != nil {
+			response.Error = err.Error()
+		}
+		writeJSON(w, http.StatusOK, response)
+	}))
+}
+
+// rpcNavigation answers with the rover location after the navigation
+func rpcNavigation(navigate func(ctx context.Context) (models.LocationDto, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		location, err := navigate(r.Context())
+		response := models.CommandResultDto{Location: location}
+		if err != nil {
+			response.Error = err.Error()
+		}
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+func rpcMethod(authorizer *Authorizer, operation models.Operation, handler http.HandlerFunc) http.Handler {
+	return authorizer.Middleware(operation, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		handler(w, r)
+	}))
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
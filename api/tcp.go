@@ -0,0 +1,248 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/utils"
+)
+
+// maxLineSize is the longest request line accepted
+const maxLineSize = 64 * 1024
+
+// TcpServer serves the rover over a line based text protocol. Each request
+// line is answered by a status line "OK <location>" or "ERR <location> - <reason>".
+// Requests:
+//   - AUTH <token>: authenticates the connection when tokens are configured
+//   - STATE: returns the rover location
+//   - MOVE <commands>: executes the commands, e.g. "MOVE ffrl", or a signed
+//     envelope given as JSON
+//   - RESET <x> <y> <direction>: lands the rover again on the location
+//   - HOME: drives the rover back to its starting location
+//   - RETREAT: drives the rover to the nearest safe zone
+//   - OBSTACLE ADD|REMOVE <x> <y>..., OBSTACLE SAVE or OBSTACLE LIST: edits the
+//     obstacles, answered by "OK <obstacles>" or "ERR <obstacles> - <reason>"
+type TcpServer struct {
+	controller *RoverController
+	obstacles  *ObstacleController
+	authorizer *Authorizer
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// NewTcpServer returns a TCP server, the OBSTACLE requests are rejected when
+// obstacles is nil
+func NewTcpServer(controller *RoverController, obstacles *ObstacleController, authorizer *Authorizer) *TcpServer {
+	return &TcpServer{
+		controller: controller,
+		obstacles:  obstacles,
+		authorizer: authorizer,
+		conns:      map[net.Conn]struct{}{},
+	}
+}
+
+// Serve accepts connections on the listener until the server is closed
+func (s *TcpServer) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return fmt.Errorf("server closed")
+	}
+	s.listener = listener
+	s.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+
+			if closed {
+				return nil
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return nil
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.serveConn(conn)
+	}
+}
+
+// Close stops accepting connections and closes the open ones
+func (s *TcpServer) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+
+	return err
+}
+
+func (s *TcpServer) serveConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		s.wg.Done()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 4096), maxLineSize)
+	writer := bufio.NewWriter(conn)
+	token := ""
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		request, argument := line, ""
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			request, argument = line[:i], strings.TrimSpace(line[i+1:])
+		}
+
+		// answer is the location, or the obstacles for the OBSTACLE requests
+		var answer string
+		var location models.LocationDto
+		var err error
+
+		switch strings.ToUpper(request) {
+		case "AUTH":
+			token = argument
+			location, err = s.state(token)
+		case "STATE":
+			location, err = s.state(token)
+		case "MOVE":
+			location, err = s.move(token, argument)
+		case "RESET":
+			location, err = s.reset(token, argument)
+		case "HOME":
+			location, err = s.navigate(token, s.controller.ReturnHome)
+		case "RETREAT":
+			location, err = s.navigate(token, s.controller.Retreat)
+		case "OBSTACLE":
+			var obstacles []models.ObstacleDto
+			obstacles, err = s.obstacle(token, argument)
+			answer = utils.ObstaclesToString(obstacles)
+		default:
+			err = fmt.Errorf("request '%s' unknown", request)
+		}
+
+		if strings.ToUpper(request) != "OBSTACLE" {
+			answer = utils.LocationToString(location)
+		}
+
+		if err != nil {
+			fmt.Fprintf(writer, "ERR %s - %s\n", answer, err)
+		} else {
+			fmt.Fprintf(writer, "OK %s\n", answer)
+		}
+		if writer.Flush() != nil {
+			return
+		}
+	}
+}
+
+func (s *TcpServer) state(token string) (models.LocationDto, error) {
+	if err := s.authorizer.Authorize(token, models.OperationReadState); err != nil {
+		return models.LocationDto{}, err
+	}
+
+	return s.controller.Location(), nil
+}
+
+func (s *TcpServer) move(token string, argument string) (models.LocationDto, error) {
+	if err := s.authorizer.Authorize(token, models.OperationSendCommands); err != nil {
+		return models.LocationDto{}, err
+	}
+
+	var commandFrame models.CommandFrameDto
+	if strings.HasPrefix(argument, "{") {
+		var envelope models.EnvelopeDto
+		if err := json.Unmarshal([]byte(argument), &envelope); err != nil {
+			return s.controller.Location(), fmt.Errorf("invalid envelope: %v", err)
+		}
+		commandFrame.Envelope = &envelope
+	} else {
+		commandFrame.Commands = strings.Split(argument, "")
+	}
+
+	return s.controller.Execute(context.Background(), commandFrame)
+}
+
+func (s *TcpServer) reset(token string, argument string) (models.LocationDto, error) {
+	if err := s.authorizer.Authorize(token, models.OperationResetRover); err != nil {
+		return models.LocationDto{}, err
+	}
+
+	fields := strings.Fields(argument)
+	if len(fields) != 3 {
+		return s.controller.Location(), fmt.Errorf("usage: RESET <x> <y> <direction>")
+	}
+	x, errX := strconv.Atoi(fields[0])
+	y, errY := strconv.Atoi(fields[1])
+	if errX != nil || errY != nil {
+		return s.controller.Location(), fmt.Errorf("invalid point '%s %s'", fields[0], fields[1])
+	}
+
+	return s.controller.Reset(models.LocationDto{
+		Point:     models.PointDto{XPoint: x, YPoint: y},
+		Direction: models.Direction(strings.ToUpper(fields[2])),
+	})
+}
+
+func (s *TcpServer) navigate(token string, navigate func(ctx context.Context) (models.LocationDto, error)) (models.LocationDto, error) {
+	if err := s.authorizer.Authorize(token, models.OperationSendCommands); err != nil {
+		return models.LocationDto{}, err
+	}
+
+	return navigate(context.Background())
+}
+
+func (s *TcpServer) obstacle(token string, argument string) ([]models.ObstacleDto, error) {
+	if s.obstacles == nil {
+		return nil, fmt.Errorf("obstacle editing not available")
+	}
+
+	operation := models.OperationEditMap
+	if strings.EqualFold(strings.TrimSpace(argument), "LIST") {
+		operation = models.OperationReadState
+	}
+	if err := s.authorizer.Authorize(token, operation); err != nil {
+		return nil, err
+	}
+
+	return s.obstacles.ExecuteCommand(argument)
+}
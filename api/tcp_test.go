@@ -0,0 +1,183 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/mars-rover-go/domains"
+)
+
+func TestTcpServer_Serve(t *testing.T) {
+	eventBus := domains.NewEventBusDomain()
+	defer eventBus.Close()
+
+	rover := newRoverDomainMocked(t, eventBus)
+	addr, closeServer := startTcpServerMocked(t, NewRoverController(rover, nil, newNavigatorDomainMocked(rover)), newAuthorizerMocked(t))
+	defer closeServer()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	tests := []struct {
+		name    string
+		request string
+		want    string
+	}{
+		{
+			name:    "State without token",
+			request: "STATE",
+			want:    "ERR (0,0)  - token missing or unknown\n",
+		},
+		{
+			name:    "Auth with an unknown token",
+			request: "AUTH stolen-token",
+			want:    "ERR (0,0)  - token missing or unknown\n",
+		},
+		{
+			name:    "Request unknown without token",
+			request: "JUMP",
+			want:    "ERR (0,0)  - request 'JUMP' unknown\n",
+		},
+		{
+			name:    "Auth as driver",
+			request: "AUTH driver-token",
+			want:    "OK (1,1) N\n",
+		},
+		{
+			name:    "Move",
+			request: "MOVE ffr",
+			want:    "OK (1,3) E\n",
+		},
+		{
+			name:    "Move with unknown command",
+			request: "move fx",
+			want:    "ERR (2,3) E - command 'x' unknown\n",
+		},
+		{
+			name:    "Reset as driver",
+			request: "RESET 5 5 s",
+			want:    "ERR (0,0)  - operation not allowed: driver cannot reset-rover\n",
+		},
+		{
+			name:    "Auth as admin",
+			request: "AUTH admin-token",
+			want:    "OK (2,3) E\n",
+		},
+		{
+			name:    "Reset on obstacle",
+			request: "RESET 2 6 N",
+			want:    "ERR (2,3) E - location is an obstacle\n",
+		},
+		{
+			name:    "Reset",
+			request: "RESET 5 5 s",
+			want:    "OK (5,5) S\n",
+		},
+		{
+			name:    "Home",
+			request: "HOME",
+			want:    "OK (1,1) N\n",
+		},
+		{
+			name:    "Retreat",
+			request: "RETREAT",
+			want:    "OK (1,1) N\n",
+		},
+		{
+			name:    "Request unknown",
+			request: "JUMP",
+			want:    "ERR (0,0)  - request 'JUMP' unknown\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fmt.Fprintf(conn, "%s\n", tt.request)
+
+			got, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("TcpServer %q = %q, want %q", tt.request, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTcpServer_ServeConcurrentClients(t *testing.T) {
+	eventBus := domains.NewEventBusDomain()
+	defer eventBus.Close()
+
+	addr, closeServer := startTcpServerMocked(t, NewRoverController(newRoverDomainMocked(t, eventBus), nil, nil), nil)
+	defer closeServer()
+
+	// Every batch turns the rover on itself, so the rover ends facing north
+	// only if the batches are not interleaved
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			conn, err := net.Dial("tcp", addr)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer conn.Close()
+			reader := bufio.NewReader(conn)
+
+			for j := 0; j < 20; j++ {
+				fmt.Fprintf(conn, "MOVE rrrr\n")
+				if _, err := reader.ReadString('\n'); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(conn, "STATE\n")
+	got, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "OK (1,1) N\n"; got != want {
+		t.Errorf("TcpServer STATE = %q, want %q", got, want)
+	}
+}
+
+func startTcpServerMocked(t *testing.T, controller *RoverController, authorizer *Authorizer) (string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewTcpServer(controller, nil, authorizer)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	return listener.Addr().String(), func() {
+		_ = server.Close()
+	}
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/mars-rover-go/domains"
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/websocket"
+)
+
+// TelemetryBufferSize is the number of events buffered for each client.
+// Events are dropped for clients too slow to keep up with the rover.
+const TelemetryBufferSize = 256
+
+// TelemetryServer streams the rover events to websocket clients as JSON
+// frames and executes the command batches they send
+type TelemetryServer struct {
+	controller *RoverController
+	eventBus   domains.IEventBusDomain
+	authorizer *Authorizer
+}
+
+// NewTelemetryServer returns a telemetry server. Clients need a token allowed
+// to read the state to connect, and to send commands to execute command frames.
+func NewTelemetryServer(controller *RoverController, eventBus domains.IEventBusDomain, authorizer *Authorizer) *TelemetryServer {
+	return &TelemetryServer{
+		controller: controller,
+		eventBus:   eventBus,
+		authorizer: authorizer,
+	}
+}
+
+func (s *TelemetryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := requestToken(r)
+	if err := s.authorizer.Authorize(token, models.OperationReadState); err != nil {
+		writeAuthError(w, err)
+		return
+	}
+
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		log.Printf("ERROR - %+v", err)
+		return
+	}
+	defer conn.Close()
+
+	// Subscribes before reading the state so no event is lost in between
+	subscription := s.eventBus.Subscribe(domains.SubscriberFunc(func(event models.EventDto) {
+		_ = writeFrame(conn, models.TelemetryFrameDto{
+			Type:     models.TelemetryFrameTypeEvent,
+			Location: event.Location,
+			Event:    &event,
+		})
+	}), TelemetryBufferSize, models.DeliveryPolicyDrop)
+	defer subscription.Unsubscribe()
+
+	if err := writeFrame(conn, models.TelemetryFrameDto{Type: models.TelemetryFrameTypeState, Location: s.controller.Location()}); err != nil {
+		return
+	}
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var commandFrame models.CommandFrameDto
+		if err := json.Unmarshal(message, &commandFrame); err != nil {
+			_ = writeFrame(conn, models.TelemetryFrameDto{
+				Type:     models.TelemetryFrameTypeError,
+				Location: s.controller.Location(),
+				Error:    fmt.Sprintf("invalid command frame: %v", err),
+			})
+			continue
+		}
+
+		if err := s.authorizer.Authorize(token, models.OperationSendCommands); err != nil {
+			_ = writeFrame(conn, models.TelemetryFrameDto{
+				Type:     models.TelemetryFrameTypeError,
+				Location: s.controller.Location(),
+				Error:    err.Error(),
+			})
+			continue
+		}
+
+		location, err := s.controller.Execute(r.Context(), commandFrame)
+		result := models.TelemetryFrameDto{Type: models.TelemetryFrameTypeResult, Location: location}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		if err := writeFrame(conn, result); err != nil {
+			return
+		}
+	}
+}
+
+func writeFrame(conn *websocket.Conn, frame models.TelemetryFrameDto) error {
+	message, err := json.Marshal(frame)
+	if err != nil {
+		return err
+	}
+
+	return conn.WriteMessage(websocket.OpText, message)
+}
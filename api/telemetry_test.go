@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mars-rover-go/domains"
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/websocket"
+)
+
+func TestTelemetryServer_ServeHTTP(t *testing.T) {
+	eventBus := domains.NewEventBusDomain()
+	defer eventBus.Close()
+
+	rover := newRoverDomainMocked(t, eventBus)
+	server := httptest.NewServer(NewTelemetryServer(NewRoverController(rover, nil, nil), eventBus, nil))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	driver := dialTelemetry(t, url)
+	defer driver.Close()
+	viewer := dialTelemetry(t, url)
+	defer viewer.Close()
+
+	for _, conn := range []*websocket.Conn{driver, viewer} {
+		frame := readTelemetryFrame(t, conn)
+		if frame.Type != models.TelemetryFrameTypeState || frame.Location.Point != (models.PointDto{XPoint: 1, YPoint: 1}) {
+			t.Fatalf("TelemetryServer state frame = %+v", frame)
+		}
+	}
+
+	message, _ := json.Marshal(models.CommandFrameDto{Commands: []string{"f", "r"}})
+	if err := driver.WriteMessage(websocket.OpText, message); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []models.EventType{models.EventTypeMoved, models.EventTypeTurned, models.EventTypeBatchCompleted}
+	got := []models.EventType{}
+	for len(got) < len(want) {
+		frame := readTelemetryFrame(t, viewer)
+		if frame.Type != models.TelemetryFrameTypeEvent {
+			t.Fatalf("TelemetryServer viewer frame = %+v, want event", frame)
+		}
+		got = append(got, frame.Event.Type)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("TelemetryServer viewer events = %v, want %v", got, want)
+			break
+		}
+	}
+
+	for {
+		frame := readTelemetryFrame(t, driver)
+		if frame.Type != models.TelemetryFrameTypeResult {
+			continue
+		}
+
+		wantLocation := models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 2}, Direction: models.DirectionEast}
+		if frame.Location != wantLocation || frame.Error != "" {
+			t.Errorf("TelemetryServer result frame = %+v, want location %v", frame, wantLocation)
+		}
+		break
+	}
+}
+
+func newRoverDomainMocked(t *testing.T, eventBus domains.IEventBusDomain) domains.IRoverDomain {
+	rover, err := domains.NewRoverDomain(
+		1,
+		models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorth},
+		domains.NewGridDomain(models.GridDto{XPointMax: 10, YPointMax: 10}),
+		domains.NewObstacleDomain([]models.ObstacleDto{{Point: models.PointDto{XPoint: 2, YPoint: 6}}}),
+		domains.NewEventStoreDomain(domains.DefaultSnapshotInterval),
+		eventBus,
+		domains.NewRealClockDomain(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rover
+}
+
+func newNavigatorDomainMocked(rover domains.IRoverDomain) domains.INavigatorDomain {
+	return domains.NewNavigatorDomain(
+		rover,
+		domains.NewGridDomain(models.GridDto{XPointMax: 10, YPointMax: 10}),
+		domains.NewObstacleDomain([]models.ObstacleDto{{Point: models.PointDto{XPoint: 2, YPoint: 6}}}),
+		models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorth},
+		models.RetreatDto{},
+	)
+}
+
+func dialTelemetry(t *testing.T, url string) *websocket.Conn {
+	conn, err := websocket.Dial(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn
+}
+
+func readTelemetryFrame(t *testing.T, conn *websocket.Conn) models.TelemetryFrameDto {
+	_, message, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var frame models.TelemetryFrameDto
+	if err := json.Unmarshal(message, &frame); err != nil {
+		t.Fatal(err)
+	}
+
+	return frame
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/utils"
+)
+
+// loadAsciiMap replaces the grid and obstacles of the configuration with the
+// ones of the ASCII map, and the starting location when the map sets it.
+// The grid wrapping and diagonals stay the configured ones.
+func loadAsciiMap(path string, config *models.ConfigurationDto, startingLocation *models.LocationDto) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	m, err := utils.ParseAsciiMap(file)
+	if err != nil {
+		return err
+	}
+
+	m.Grid.Wrapping = config.Grid.Wrapping
+	m.Grid.Diagonals = config.Grid.Diagonals
+	config.Grid = m.Grid
+	config.Obstacle = m.Obstacles
+	if m.Start != nil {
+		switch m.Start.Direction {
+		case models.DirectionNorthEast, models.DirectionNorthWest, models.DirectionSouthEast, models.DirectionSouthWest:
+			if !m.Grid.Diagonals {
+				return fmt.Errorf("map start faces %s, the grid has no diagonals", m.Start.Direction)
+			}
+		}
+		*startingLocation = *m.Start
+	}
+
+	return nil
+}
+
+// writeAsciiMap writes the grid, obstacles and starting location as an ASCII
+// map, e.g. "map -out mars.txt"
+func writeAsciiMap(args []string, config models.ConfigurationDto, startingLocation models.LocationDto) error {
+	flags := flag.NewFlagSet("map", flag.ContinueOnError)
+	out := flags.String("out", "", "ASCII map file written, the standard output when not set")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	return utils.WriteAsciiMap(w, models.MapDto{
+		Grid:      config.Grid,
+		Obstacles: config.Obstacle,
+		Start:     &startingLocation,
+	})
+}
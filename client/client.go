@@ -0,0 +1,202 @@
+// Package client is the Go client of the RoverService described in
+// proto/rover/v1/rover.proto, for programs controlling a rover served by
+// "mars-rover-go serve". It is written against the Dto types of the models
+// package, the JSON the server exchanges.
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/mars-rover-go/models"
+)
+
+const pathPrefix = "/rover.v1.RoverService/"
+
+type RoverServiceClient interface {
+	// ExecuteCommands executes a command batch on the rover
+	ExecuteCommands(ctx context.Context, request models.CommandFrameDto) (models.CommandResultDto, error)
+	// ReturnHome drives the rover back to its starting location
+	ReturnHome(ctx context.Context) (models.CommandResultDto, error)
+	// Retreat drives the rover to the nearest safe zone, or back to its starting location when there is none
+	Retreat(ctx context.Context) (models.CommandResultDto, error)
+	// GetState returns the rover location
+	GetState(ctx context.Context) (models.StateDto, error)
+	// StreamEvents streams the rover events after the sequence until the context is done
+	StreamEvents(ctx context.Context, request models.StreamEventsRequestDto) (EventStream, error)
+	// Plan returns the events a command batch would produce, without moving the rover
+	Plan(ctx context.Context, request models.PlanRequestDto) (models.PlanDto, error)
+	// ListObstacles returns the current obstacles
+	ListObstacles(ctx context.Context) (models.ObstaclesDto, error)
+	// AddObstacles adds the obstacles, none is added when one is out the grid or under the rover
+	AddObstacles(ctx context.Context, request models.ObstaclesDto) (models.ObstaclesDto, error)
+	// RemoveObstacles removes the obstacles
+	RemoveObstacles(ctx context.Context, request models.ObstaclesDto) (models.ObstaclesDto, error)
+	// SaveObstacles writes the current obstacles to the ASCII map or the configuration
+	SaveObstacles(ctx context.Context) (models.ObstaclesDto, error)
+}
+
+type EventStream interface {
+	// Recv returns the next event, blocking until it is received
+	Recv() (models.EventDto, error)
+	// Close stops the stream
+	Close() error
+}
+
+type roverServiceClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewRoverServiceClient returns a client of the rover served at baseURL,
+// e.g. "http://localhost:8080". The token is sent as a bearer token when not empty.
+func NewRoverServiceClient(baseURL string, token string, httpClient *http.Client) RoverServiceClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &roverServiceClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: httpClient,
+	}
+}
+
+func (c *roverServiceClient) ExecuteCommands(ctx context.Context, request models.CommandFrameDto) (models.CommandResultDto, error) {
+	var response models.CommandResultDto
+	err := c.call(ctx, "ExecuteCommands", request, &response)
+
+	return response, err
+}
+
+func (c *roverServiceClient) ReturnHome(ctx context.Context) (models.CommandResultDto, error) {
+	var response models.CommandResultDto
+	err := c.call(ctx, "ReturnHome", struct{}{}, &response)
+
+	return response, err
+}
+
+func (c *roverServiceClient) Retreat(ctx context.Context) (models.CommandResultDto, error) {
+	var response models.CommandResultDto
+	err := c.call(ctx, "Retreat", struct{}{}, &response)
+
+	return response, err
+}
+
+func (c *roverServiceClient) GetState(ctx context.Context) (models.StateDto, error) {
+	var response models.StateDto
+	err := c.call(ctx, "GetState", struct{}{}, &response)
+
+	return response, err
+}
+
+func (c *roverServiceClient) StreamEvents(ctx context.Context, request models.StreamEventsRequestDto) (EventStream, error) {
+	response, err := c.post(ctx, "StreamEvents", request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventStream{
+		body:    response.Body,
+		decoder: json.NewDecoder(bufio.NewReader(response.Body)),
+	}, nil
+}
+
+func (c *roverServiceClient) Plan(ctx context.Context, request models.PlanRequestDto) (models.PlanDto, error) {
+	var response models.PlanDto
+	err := c.call(ctx, "Plan", request, &response)
+
+	return response, err
+}
+
+func (c *roverServiceClient) ListObstacles(ctx context.Context) (models.ObstaclesDto, error) {
+	var response models.ObstaclesDto
+	err := c.call(ctx, "ListObstacles", struct{}{}, &response)
+
+	return response, err
+}
+
+func (c *roverServiceClient) AddObstacles(ctx context.Context, request models.ObstaclesDto) (models.ObstaclesDto, error) {
+	var response models.ObstaclesDto
+	err := c.call(ctx, "AddObstacles", request, &response)
+
+	return response, err
+}
+
+func (c *roverServiceClient) RemoveObstacles(ctx context.Context, request models.ObstaclesDto) (models.ObstaclesDto, error) {
+	var response models.ObstaclesDto
+	err := c.call(ctx, "RemoveObstacles", request, &response)
+
+	return response, err
+}
+
+func (c *roverServiceClient) SaveObstacles(ctx context.Context) (models.ObstaclesDto, error) {
+	var response models.ObstaclesDto
+	err := c.call(ctx, "SaveObstacles", struct{}{}, &response)
+
+	return response, err
+}
+
+func (c *roverServiceClient) call(ctx context.Context, method string, request interface{}, response interface{}) error {
+	httpResponse, err := c.post(ctx, method, request)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	return json.NewDecoder(httpResponse.Body).Decode(response)
+}
+
+// post sends the request, returning an error when the response status is not OK
+func (c *roverServiceClient) post(ctx context.Context, method string, request interface{}) (*http.Response, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+pathPrefix+method, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		httpRequest.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	httpResponse, err := c.httpClient.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpResponse.StatusCode != http.StatusOK {
+		defer httpResponse.Body.Close()
+		message, _ := ioutil.ReadAll(httpResponse.Body)
+		return nil, fmt.Errorf("%s failed: %s - %s", method, httpResponse.Status, strings.TrimSpace(string(message)))
+	}
+
+	return httpResponse, nil
+}
+
+type eventStream struct {
+	body    io.ReadCloser
+	decoder *json.Decoder
+}
+
+func (s *eventStream) Recv() (models.EventDto, error) {
+	var event models.EventDto
+	err := s.decoder.Decode(&event)
+
+	return event, err
+}
+
+func (s *eventStream) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,340 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mars-rover-go/api"
+	"github.com/mars-rover-go/domains"
+	"github.com/mars-rover-go/models"
+)
+
+func TestRoverServiceClient(t *testing.T) {
+	eventStore := domains.NewEventStoreDomain(domains.DefaultSnapshotInterval)
+	eventBus := domains.NewEventBusDomain()
+	defer eventBus.Close()
+
+	home := models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorth}
+	gridDomain := domains.NewGridDomain(models.GridDto{XPointMax: 10, YPointMax: 10})
+	obstacleDomain := domains.NewObstacleDomain([]models.ObstacleDto{{Point: models.PointDto{XPoint: 1, YPoint: 3}}})
+	rover, err := domains.NewRoverDomain(
+		1,
+		home,
+		gridDomain,
+		obstacleDomain,
+		eventStore,
+		eventBus,
+		domains.NewRealClockDomain(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(api.NewRpcHandler(1, api.NewRoverController(rover, nil, domains.NewNavigatorDomain(rover, gridDomain, obstacleDomain, home, models.RetreatDto{})), nil, eventStore, eventBus, nil))
+	defer server.Close()
+
+	c := NewRoverServiceClient(server.URL, "", nil)
+	ctx := context.Background()
+
+	plan, err := c.Plan(ctx, models.PlanRequestDto{Commands: []string{"f", "f"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Error == "" || len(plan.Events) != 3 || plan.Events[1].Type != models.EventTypeObstacleEncountered {
+		t.Errorf("RoverServiceClient.Plan() = %+v, want obstacle encountered", plan)
+	}
+
+	state, err := c.GetState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Location != home {
+		t.Errorf("RoverServiceClient.GetState() = %v, want %v", state.Location, home)
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.StreamEvents(streamCtx, models.StreamEventsRequestDto{FromSequence: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	result, err := c.ExecuteCommands(ctx, models.CommandFrameDto{Commands: []string{"r", "f"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (models.LocationDto{Point: models.PointDto{XPoint: 2, YPoint: 1}, Direction: models.DirectionEast}); result.Location != want || result.Error != "" {
+		t.Errorf("RoverServiceClient.ExecuteCommands() = %+v, want %v", result, want)
+	}
+
+	want := []models.EventType{models.EventTypeLanded, models.EventTypeTurned, models.EventTypeMoved, models.EventTypeBatchCompleted}
+	got := []models.EventType{}
+	for len(got) < len(want) {
+		event, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, event.Type)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RoverServiceClient.StreamEvents() = %v, want %v", got, want)
+	}
+
+	result, err = c.ReturnHome(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Location != home || result.Error != "" {
+		t.Errorf("RoverServiceClient.ReturnHome() = %+v, want %v", result, home)
+	}
+}
+
+func TestRoverServiceClient_StreamEventsSlowReader(t *testing.T) {
+	eventStore := domains.NewEventStoreDomain(domains.DefaultSnapshotInterval)
+	eventBus := domains.NewEventBusDomain()
+	defer eventBus.Close()
+
+	home := models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorth}
+	gridDomain := domains.NewGridDomain(models.GridDto{XPointMax: 10, YPointMax: 10})
+	obstacleDomain := domains.NewObstacleDomain([]models.ObstacleDto{})
+	rover, err := domains.NewRoverDomain(1, home, gridDomain, obstacleDomain, eventStore, eventBus, domains.NewRealClockDomain())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(api.NewRpcHandler(1, api.NewRoverController(rover, nil, domains.NewNavigatorDomain(rover, gridDomain, obstacleDomain, home, models.RetreatDto{})), nil, eventStore, eventBus, nil))
+	defer server.Close()
+
+	c := NewRoverServiceClient(server.URL, "", nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := c.StreamEvents(ctx, models.StreamEventsRequestDto{FromSequence: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	// Many more events than the buffers hold, published before any is read
+	commands := make([]string, 20*api.TelemetryBufferSize)
+	for i := range commands {
+		commands[i] = "l"
+	}
+	if _, err := c.ExecuteCommands(ctx, models.CommandFrameDto{Commands: commands}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Landed, the turns and BatchCompleted, none missing
+	want := len(commands) + 2
+	for sequence := 1; sequence <= want; sequence++ {
+		event, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.Sequence != sequence {
+			t.Fatalf("RoverServiceClient.StreamEvents() sequence = %d, want %d", event.Sequence, sequence)
+		}
+	}
+}
+
+// protoDtos are the Dto types the proto messages are noted with
+var protoDtos = map[string]reflect.Type{
+	"PointDto":               reflect.TypeOf(models.PointDto{}),
+	"LocationDto":            reflect.TypeOf(models.LocationDto{}),
+	"EnvelopeDto":            reflect.TypeOf(models.EnvelopeDto{}),
+	"EventDto":               reflect.TypeOf(models.EventDto{}),
+	"CommandFrameDto":        reflect.TypeOf(models.CommandFrameDto{}),
+	"CommandResultDto":       reflect.TypeOf(models.CommandResultDto{}),
+	"StateDto":               reflect.TypeOf(models.StateDto{}),
+	"StreamEventsRequestDto": reflect.TypeOf(models.StreamEventsRequestDto{}),
+	"PlanRequestDto":         reflect.TypeOf(models.PlanRequestDto{}),
+	"PlanDto":                reflect.TypeOf(models.PlanDto{}),
+	"ObstacleDto":            reflect.TypeOf(models.ObstacleDto{}),
+	"ObstaclesDto":           reflect.TypeOf(models.ObstaclesDto{}),
+}
+
+type protoField struct {
+	repeated bool
+	typeName string
+	jsonName string
+}
+
+type protoMessage struct {
+	dto string
+	// omitted are the Dto fields the message leaves out
+	omitted []string
+	fields  []protoField
+}
+
+type protoRpc struct {
+	name     string
+	request  string
+	response string
+	stream   bool
+}
+
+var (
+	protoDtoComment = regexp.MustCompile(`^// models\.(\w+)(?: without (\w+(?:, \w+)*))?$`)
+	protoMessageRe  = regexp.MustCompile(`^message (\w+) \{(\})?$`)
+	protoFieldRe    = regexp.MustCompile(`^\s+(repeated )?(\w+) \w+ = \d+ \[json_name = "(\w+)"\];$`)
+	protoRpcRe      = regexp.MustCompile(`^\s+rpc (\w+)\((\w+)\) returns \((stream )?(\w+)\);$`)
+)
+
+// TestRoverServiceClient_Proto checks the proto messages against the Dto
+// types they are noted with, and the service methods against the client
+func TestRoverServiceClient_Proto(t *testing.T) {
+	messages, rpcs := parseProtoMocked(t, "../proto/rover/v1/rover.proto")
+
+	for name, message := range messages {
+		if message.dto == "" {
+			if len(message.fields) > 0 {
+				t.Errorf("proto message %s has fields and no Dto type", name)
+			}
+			continue
+		}
+		dto, ok := protoDtos[message.dto]
+		if !ok {
+			t.Errorf("proto message %s Dto type models.%s unknown", name, message.dto)
+			continue
+		}
+
+		dtoFields := map[string]reflect.Type{}
+		for i := 0; i < dto.NumField(); i++ {
+			dtoFields[jsonName(dto.Field(i))] = dto.Field(i).Type
+		}
+		for _, omitted := range message.omitted {
+			if _, ok := dtoFields[omitted]; !ok {
+				t.Errorf("proto message %s omits field %s not in models.%s", name, omitted, message.dto)
+			}
+			delete(dtoFields, omitted)
+		}
+
+		for _, field := range message.fields {
+			fieldType, ok := dtoFields[field.jsonName]
+			if !ok {
+				t.Errorf("proto message %s field %s not in models.%s", name, field.jsonName, message.dto)
+				continue
+			}
+			delete(dtoFields, field.jsonName)
+
+			if field.repeated != (fieldType.Kind() == reflect.Slice) {
+				t.Errorf("proto message %s field %s repeated %v, models.%s field is %v", name, field.jsonName, field.repeated, message.dto, fieldType)
+			}
+			if fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if nested, ok := messages[field.typeName]; ok && protoDtos[nested.dto] != fieldType {
+				t.Errorf("proto message %s field %s is a %s, models.%s field is %v", name, field.jsonName, field.typeName, message.dto, fieldType)
+			}
+		}
+		for missing := range dtoFields {
+			t.Errorf("proto message %s has no field %s of models.%s", name, missing, message.dto)
+		}
+	}
+
+	client := reflect.TypeOf((*RoverServiceClient)(nil)).Elem()
+	if len(rpcs) != client.NumMethod() {
+		t.Errorf("proto service has %d methods, RoverServiceClient %d", len(rpcs), client.NumMethod())
+	}
+	for _, rpc := range rpcs {
+		method, ok := client.MethodByName(rpc.name)
+		if !ok {
+			t.Errorf("proto method %s not in RoverServiceClient", rpc.name)
+			continue
+		}
+
+		request := []reflect.Type{}
+		for i := 1; i < method.Type.NumIn(); i++ {
+			request = append(request, method.Type.In(i))
+		}
+		wantRequest := []reflect.Type{}
+		if dto := protoDtos[messages[rpc.request].dto]; dto != nil {
+			wantRequest = append(wantRequest, dto)
+		}
+		if !reflect.DeepEqual(request, wantRequest) {
+			t.Errorf("RoverServiceClient.%s request = %v, want %v for %s", rpc.name, request, wantRequest, rpc.request)
+		}
+
+		response := method.Type.Out(0)
+		if rpc.stream {
+			recv, _ := response.MethodByName("Recv")
+			response = recv.Type.Out(0)
+		}
+		if want := protoDtos[messages[rpc.response].dto]; response != want {
+			t.Errorf("RoverServiceClient.%s response = %v, want %v for %s", rpc.name, response, want, rpc.response)
+		}
+	}
+}
+
+// jsonName returns the JSON name of the struct field
+func jsonName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" {
+		return name
+	}
+
+	return field.Name
+}
+
+// parseProtoMocked reads the messages, with the Dto type noted on each as
+// "// models.XxxDto" or "// models.XxxDto without Field, Field", and the
+// service methods of the proto file
+func parseProtoMocked(t *testing.T, path string) (map[string]protoMessage, []protoRpc) {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	messages := map[string]protoMessage{}
+	rpcs := []protoRpc{}
+	dto := protoMessage{}
+	message := ""
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case protoDtoComment.MatchString(line):
+			match := protoDtoComment.FindStringSubmatch(line)
+			dto = protoMessage{dto: match[1]}
+			if match[2] != "" {
+				dto.omitted = strings.Split(match[2], ", ")
+			}
+		case protoMessageRe.MatchString(line):
+			match := protoMessageRe.FindStringSubmatch(line)
+			messages[match[1]] = dto
+			if match[2] == "" {
+				message = match[1]
+			}
+			dto = protoMessage{}
+		case protoFieldRe.MatchString(line) && message != "":
+			match := protoFieldRe.FindStringSubmatch(line)
+			m := messages[message]
+			m.fields = append(m.fields, protoField{repeated: match[1] != "", typeName: match[2], jsonName: match[3]})
+			messages[message] = m
+		case protoRpcRe.MatchString(line):
+			match := protoRpcRe.FindStringSubmatch(line)
+			rpcs = append(rpcs, protoRpc{name: match[1], request: match[2], response: match[4], stream: match[3] != ""})
+		case line == "}":
+			message = ""
+		case !strings.HasPrefix(line, "//"):
+			dto = protoMessage{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) == 0 || len(rpcs) == 0 {
+		t.Fatalf("proto file %s has no message or no service method", path)
+	}
+
+	return messages, rpcs
+}
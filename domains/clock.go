@@ -0,0 +1,280 @@
+package domains
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// RealClockDomain follows the wall clock
+type RealClockDomain struct {
+	start time.Time
+
+	mu      sync.Mutex
+	queue   scheduledQueue
+	seq     int
+	running bool
+	wakeup  chan struct{}
+}
+
+func NewRealClockDomain() IClockDomain {
+	return &RealClockDomain{
+		start:  time.Now(),
+		wakeup: make(chan struct{}, 1),
+	}
+}
+
+func (c *RealClockDomain) Now() time.Duration {
+	return time.Since(c.start)
+}
+
+func (c *RealClockDomain) Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// AfterFunc calls the functions one at a time from a scheduler goroutine,
+// in the order they are due
+func (c *RealClockDomain) AfterFunc(d time.Duration, fn func()) {
+	c.mu.Lock()
+	c.seq++
+	heap.Push(&c.queue, &scheduledItem{at: c.Now() + d, seq: c.seq, fn: fn})
+	if !c.running {
+		c.running = true
+		go c.run()
+	}
+	c.mu.Unlock()
+
+	select {
+	case c.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// Go starts the task on its own goroutine, the wall clock does not wait for it
+func (c *RealClockDomain) Go(task func()) {
+	go task()
+}
+
+func (c *RealClockDomain) run() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		c.mu.Lock()
+		var due *scheduledItem
+		wait := time.Hour
+		if c.queue.Len() > 0 {
+			next := c.queue[0]
+			if wait = next.at - c.Now(); wait <= 0 {
+				due = heap.Pop(&c.queue).(*scheduledItem)
+			}
+		}
+		c.mu.Unlock()
+
+		if due != nil {
+			due.fn()
+			continue
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-timer.C:
+		case <-c.wakeup:
+		}
+	}
+}
+
+// SimulationClockDomain is a virtual clock advancing only when asked to,
+// running the scheduled functions and waking the sleeping tasks in time
+// order. The tasks sleeping on the clock must be started with Go so the clock
+// waits for them to sleep again or finish before advancing: the simulation is
+// deterministic and runs faster than real time.
+// A task observes its context cancellation when its sleep ends.
+type SimulationClockDomain struct {
+	mu     sync.Mutex
+	idle   *sync.Cond
+	now    time.Duration
+	tick   time.Duration
+	queue  scheduledQueue
+	seq    int
+	active int
+}
+
+func NewSimulationClockDomain(tick time.Duration) *SimulationClockDomain {
+	c := &SimulationClockDomain{tick: tick}
+	c.idle = sync.NewCond(&c.mu)
+
+	return c
+}
+
+func (c *SimulationClockDomain) Now() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Sleep blocks the task until the clock reaches the end of the duration
+func (c *SimulationClockDomain) Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 || ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	wake := make(chan struct{})
+
+	c.mu.Lock()
+	c.schedule(d, func() {
+		c.mu.Lock()
+		c.active++
+		c.mu.Unlock()
+		close(wake)
+	})
+	c.active--
+	c.idle.Broadcast()
+	c.mu.Unlock()
+
+	<-wake
+	return ctx.Err()
+}
+
+func (c *SimulationClockDomain) AfterFunc(d time.Duration, fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.schedule(d, fn)
+}
+
+// Go starts a task allowed to sleep on the clock
+func (c *SimulationClockDomain) Go(task func()) {
+	c.mu.Lock()
+	c.active++
+	c.mu.Unlock()
+
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			c.active--
+			c.idle.Broadcast()
+			c.mu.Unlock()
+		}()
+
+		task()
+	}()
+}
+
+// Tick advances the clock by one tick
+func (c *SimulationClockDomain) Tick() {
+	c.Advance(c.tick)
+}
+
+// Advance advances the clock by the duration
+func (c *SimulationClockDomain) Advance(d time.Duration) {
+	c.RunUntil(c.Now() + d)
+}
+
+// RunUntil advances the clock to the time, running everything scheduled until then
+func (c *SimulationClockDomain) RunUntil(t time.Duration) {
+	for {
+		c.mu.Lock()
+		for c.active > 0 {
+			c.idle.Wait()
+		}
+
+		if c.queue.Len() == 0 || c.queue[0].at > t {
+			if t > c.now {
+				c.now = t
+			}
+			c.mu.Unlock()
+			return
+		}
+
+		next := heap.Pop(&c.queue).(*scheduledItem)
+		c.now = next.at
+		c.mu.Unlock()
+
+		next.fn()
+	}
+}
+
+// Run advances the clock until nothing is scheduled, returning the final time
+func (c *SimulationClockDomain) Run() time.Duration {
+	for {
+		c.mu.Lock()
+		for c.active > 0 {
+			c.idle.Wait()
+		}
+
+		if c.queue.Len() == 0 {
+			defer c.mu.Unlock()
+			return c.now
+		}
+		next := c.queue[0].at
+		c.mu.Unlock()
+
+		c.RunUntil(next)
+	}
+}
+
+// schedule queues the function, the clock must be locked
+func (c *SimulationClockDomain) schedule(d time.Duration, fn func()) {
+	c.seq++
+	heap.Push(&c.queue, &scheduledItem{at: c.now + d, seq: c.seq, fn: fn})
+}
+
+type scheduledItem struct {
+	at  time.Duration
+	seq int
+	fn  func()
+}
+
+// scheduledQueue is a heap of the scheduled functions ordered by time, then
+// by scheduling order
+type scheduledQueue []*scheduledItem
+
+func (q scheduledQueue) Len() int {
+	return len(q)
+}
+
+func (q scheduledQueue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+
+	return q[i].seq < q[j].seq
+}
+
+func (q scheduledQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *scheduledQueue) Push(x interface{}) {
+	*q = append(*q, x.(*scheduledItem))
+}
+
+func (q *scheduledQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return item
+}
@@ -0,0 +1,128 @@
+package domains
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSimulationClockDomain_RunUntil(t *testing.T) {
+	type scheduled struct {
+		after time.Duration
+		name  string
+	}
+
+	tests := []struct {
+		name      string
+		scheduled []scheduled
+		until     time.Duration
+		want      []string
+		wantNow   time.Duration
+	}{
+		{
+			name:      "Nothing scheduled",
+			scheduled: []scheduled{},
+			until:     5 * time.Second,
+			want:      []string{},
+			wantNow:   5 * time.Second,
+		},
+		{
+			name: "Functions called in time order",
+			scheduled: []scheduled{
+				{after: 3 * time.Second, name: "c"},
+				{after: 1 * time.Second, name: "a"},
+				{after: 2 * time.Second, name: "b"},
+			},
+			until:   5 * time.Second,
+			want:    []string{"a", "b", "c"},
+			wantNow: 5 * time.Second,
+		},
+		{
+			name: "Functions due at the same time called in scheduling order",
+			scheduled: []scheduled{
+				{after: 1 * time.Second, name: "a"},
+				{after: 1 * time.Second, name: "b"},
+				{after: 1 * time.Second, name: "c"},
+			},
+			until:   1 * time.Second,
+			want:    []string{"a", "b", "c"},
+			wantNow: 1 * time.Second,
+		},
+		{
+			name: "Functions due later not called",
+			scheduled: []scheduled{
+				{after: 1 * time.Second, name: "a"},
+				{after: 10 * time.Second, name: "b"},
+			},
+			until:   5 * time.Second,
+			want:    []string{"a"},
+			wantNow: 5 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewSimulationClockDomain(time.Second)
+
+			got := []string{}
+			for _, s := range tt.scheduled {
+				s := s
+				c.AfterFunc(s.after, func() { got = append(got, s.name) })
+			}
+
+			c.RunUntil(tt.until)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SimulationClockDomain.RunUntil() called %v, want %v", got, tt.want)
+			}
+			if now := c.Now(); now != tt.wantNow {
+				t.Errorf("SimulationClockDomain.Now() = %v, want %v", now, tt.wantNow)
+			}
+		})
+	}
+}
+
+func TestSimulationClockDomain_Sleep(t *testing.T) {
+	c := NewSimulationClockDomain(time.Second)
+
+	// The tasks and the scheduled functions interleave by simulated time only
+	got := []string{}
+	c.Go(func() {
+		for i := 0; i < 3; i++ {
+			_ = c.Sleep(context.Background(), 2*time.Second)
+			got = append(got, "task "+c.Now().String())
+		}
+	})
+	c.AfterFunc(3*time.Second, func() { got = append(got, "func "+c.Now().String()) })
+
+	c.Tick()
+	if len(got) != 0 {
+		t.Fatalf("SimulationClockDomain after one tick = %v, want nothing", got)
+	}
+
+	end := c.Run()
+
+	want := []string{"task 2s", "func 3s", "task 4s", "task 6s"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SimulationClockDomain sleeps = %v, want %v", got, want)
+	}
+	if end != 6*time.Second {
+		t.Errorf("SimulationClockDomain.Run() = %v, want %v", end, 6*time.Second)
+	}
+}
+
+func TestRealClockDomain_AfterFunc(t *testing.T) {
+	c := NewRealClockDomain()
+
+	called := make(chan int, 3)
+	c.AfterFunc(20*time.Millisecond, func() { called <- 3 })
+	c.AfterFunc(10*time.Millisecond, func() { called <- 1 })
+	c.AfterFunc(10*time.Millisecond, func() { called <- 2 })
+
+	got := []int{<-called, <-called, <-called}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RealClockDomain.AfterFunc() called %v, want %v", got, want)
+	}
+	if now := c.Now(); now < 20*time.Millisecond {
+		t.Errorf("RealClockDomain.Now() = %v, want at least %v", now, 20*time.Millisecond)
+	}
+}
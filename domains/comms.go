@@ -0,0 +1,341 @@
+package domains
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/mars-rover-go/models"
+)
+
+// DownlinkBufferSize is the number of downlinks received and not yet read
+// before the downlink delivery waits for the operator
+const DownlinkBufferSize = 64
+
+// retransmitMargin is added to the round trip time to get the default retransmit timeout
+const retransmitMargin = 100 * time.Millisecond
+
+// CommsDomain simulates the Earth-Mars communications. Command batches are
+// framed with a sequence number and a CRC and sent over the uplink, the rover
+// acknowledges them and reports its telemetry over the downlink.
+// The ground side retransmits a batch until its telemetry is received and the
+// rover side executes the batches once, in sequence order, requesting the
+// retransmission of corrupted or missing ones.
+// The frames are delivered on the clock, the batches are executed and the
+// downlinks handed to the operator on their own clock tasks so a long batch or
+// a slow operator does not hold up the frames in flight. On the simulation
+// clock the time does not advance while a downlink waits for the operator.
+type CommsDomain struct {
+	rover             IRoverDomain
+	clock             IClockDomain
+	durations         models.CommandDurationDto
+	uplink            ILinkDomain
+	downlink          ILinkDomain
+	downlinks         chan models.DownlinkDto
+	retransmitTimeout time.Duration
+
+	// Ground side
+	mu         sync.Mutex
+	sequence   uint32
+	pending    map[uint32][]byte
+	acked      map[uint32]bool
+	closed     bool
+	delivery   []models.DownlinkDto
+	delivering bool
+
+	// deliverMu keeps the downlinks channel open while a downlink is delivered
+	deliverMu sync.Mutex
+
+	// Rover side
+	roverMu   sync.Mutex
+	expected  uint32
+	received  map[uint32][]string
+	results   map[uint32]models.DownlinkDto
+	queue     []uint32
+	executing bool
+
+	// ctx cancels the running batch on close
+	ctx       context.Context
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewCommsDomain returns the communications with the rover, each command of
+// the batches delivered completing after its duration
+func NewCommsDomain(rover IRoverDomain, comms models.CommsDto, durations models.CommandDurationDto, clock IClockDomain) ICommsDomain {
+	lightTime := time.Duration(comms.LightTimeMilliseconds) * time.Millisecond
+
+	retransmitTimeout := time.Duration(comms.RetransmitTimeoutMilliseconds) * time.Millisecond
+	if retransmitTimeout <= 0 {
+		retransmitTimeout = 2*lightTime + retransmitMargin
+	}
+
+	downlink := comms.Link
+	downlink.Seed++
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &CommsDomain{
+		rover:             rover,
+		clock:             clock,
+		durations:         durations,
+		uplink:            NewLinkDomain(clock, lightTime, comms.Link),
+		downlink:          NewLinkDomain(clock, lightTime, downlink),
+		downlinks:         make(chan models.DownlinkDto, DownlinkBufferSize),
+		retransmitTimeout: retransmitTimeout,
+		pending:           map[uint32][]byte{},
+		acked:             map[uint32]bool{},
+		expected:          1,
+		received:          map[uint32][]string{},
+		results:           map[uint32]models.DownlinkDto{},
+		ctx:               ctx,
+		cancel:            cancel,
+		done:              make(chan struct{}),
+	}
+}
+
+func (c *CommsDomain) Uplink(commands []string) int {
+	payload, _ := json.Marshal(commands)
+
+	c.mu.Lock()
+	c.sequence++
+	sequence := c.sequence
+	frame := EncodeFrame(models.FrameDto{Sequence: sequence, Type: models.FrameTypeCommands, Payload: payload})
+	c.pending[sequence] = frame
+	c.mu.Unlock()
+
+	c.uplink.Transmit(frame, c.receive)
+	c.scheduleRetransmit(sequence)
+
+	return int(sequence)
+}
+
+func (c *CommsDomain) Downlinks() <-chan models.DownlinkDto {
+	return c.downlinks
+}
+
+func (c *CommsDomain) Close() {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.closed = true
+		c.mu.Unlock()
+
+		close(c.done)
+		c.cancel()
+		c.uplink.Close()
+		c.downlink.Close()
+
+		c.deliverMu.Lock()
+		close(c.downlinks)
+		c.deliverMu.Unlock()
+	})
+}
+
+// scheduleRetransmit sends the batch again every retransmit timeout until its
+// telemetry is received
+func (c *CommsDomain) scheduleRetransmit(sequence uint32) {
+	c.clock.AfterFunc(c.retransmitTimeout, func() {
+		if c.retransmit(sequence) {
+			c.scheduleRetransmit(sequence)
+		}
+	})
+}
+
+// retransmit sends the batch again if it is still waiting for its telemetry
+func (c *CommsDomain) retransmit(sequence uint32) bool {
+	c.mu.Lock()
+	frame, isPending := c.pending[sequence]
+	closed := c.closed
+	c.mu.Unlock()
+
+	if !isPending || closed {
+		return false
+	}
+
+	c.uplink.Transmit(frame, c.receive)
+	return true
+}
+
+// receive runs on the rover side when an uplink frame arrives
+func (c *CommsDomain) receive(data []byte) {
+	frame, err := DecodeFrame(data)
+	if err != nil {
+		c.roverMu.Lock()
+		expected := c.expected
+		c.roverMu.Unlock()
+
+		c.send(models.FrameDto{Sequence: expected, Type: models.FrameTypeNack})
+		return
+	}
+	if frame.Type != models.FrameTypeCommands {
+		return
+	}
+
+	var commands []string
+	if err := json.Unmarshal(frame.Payload, &commands); err != nil {
+		c.send(models.FrameDto{Sequence: frame.Sequence, Type: models.FrameTypeNack})
+		return
+	}
+
+	c.roverMu.Lock()
+	telemetry, executed := c.results[frame.Sequence]
+	queued := frame.Sequence < c.expected
+	if !queued {
+		c.received[frame.Sequence] = commands
+	}
+	missing := frame.Sequence > c.expected
+	expected := c.expected
+	c.roverMu.Unlock()
+
+	// Batch already executed, its acknowledgement or telemetry was lost
+	if executed {
+		c.sendDownlink(frame.Sequence, models.FrameTypeAck, models.DownlinkDto{BatchId: telemetry.BatchId, Type: models.DownlinkTypeAck})
+		c.sendDownlink(frame.Sequence, models.FrameTypeTelemetry, telemetry)
+		return
+	}
+
+	c.sendDownlink(frame.Sequence, models.FrameTypeAck, models.DownlinkDto{
+		BatchId:  int(frame.Sequence),
+		Type:     models.DownlinkTypeAck,
+		Location: c.rover.Location(),
+	})
+	// Batch queued or executing, its telemetry is sent once executed
+	if queued {
+		return
+	}
+
+	if missing {
+		c.send(models.FrameDto{Sequence: expected, Type: models.FrameTypeNack})
+	}
+
+	c.roverMu.Lock()
+	defer c.roverMu.Unlock()
+
+	for {
+		if _, ok := c.received[c.expected]; !ok {
+			break
+		}
+		c.queue = append(c.queue, c.expected)
+		c.expected++
+	}
+	if len(c.queue) > 0 && !c.executing {
+		c.executing = true
+		c.clock.Go(c.execute)
+	}
+}
+
+// execute runs the queued batches in sequence order on the rover side,
+// sending the telemetry of each one
+func (c *CommsDomain) execute() {
+	for {
+		c.roverMu.Lock()
+		if len(c.queue) == 0 {
+			c.executing = false
+			c.roverMu.Unlock()
+			return
+		}
+		sequence := c.queue[0]
+		c.queue = c.queue[1:]
+		commands := c.received[sequence]
+		delete(c.received, sequence)
+		c.roverMu.Unlock()
+
+		telemetry := models.DownlinkDto{BatchId: int(sequence), Type: models.DownlinkTypeTelemetry}
+		location, err := c.rover.ExecuteTimedCommands(c.ctx, commands, c.durations)
+		telemetry.Location = location
+		if err != nil {
+			telemetry.Error = err.Error()
+		}
+
+		c.roverMu.Lock()
+		c.results[sequence] = telemetry
+		c.roverMu.Unlock()
+
+		c.sendDownlink(sequence, models.FrameTypeTelemetry, telemetry)
+	}
+}
+
+// sendDownlink transmits a downlink from the rover side
+func (c *CommsDomain) sendDownlink(sequence uint32, frameType models.FrameType, downlink models.DownlinkDto) {
+	payload, _ := json.Marshal(downlink)
+	c.send(models.FrameDto{Sequence: sequence, Type: frameType, Payload: payload})
+}
+
+func (c *CommsDomain) send(frame models.FrameDto) {
+	c.downlink.Transmit(EncodeFrame(frame), c.receiveDownlink)
+}
+
+// receiveDownlink runs on the ground side when a downlink frame arrives
+func (c *CommsDomain) receiveDownlink(data []byte) {
+	// Corrupted downlinks are discarded, the batch is retransmitted on timeout
+	frame, err := DecodeFrame(data)
+	if err != nil {
+		return
+	}
+
+	if frame.Type == models.FrameTypeNack {
+		c.retransmit(frame.Sequence)
+		return
+	}
+
+	var downlink models.DownlinkDto
+	if err := json.Unmarshal(frame.Payload, &downlink); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	isNew := false
+	switch frame.Type {
+	case models.FrameTypeAck:
+		isNew = !c.acked[frame.Sequence]
+		c.acked[frame.Sequence] = true
+	case models.FrameTypeTelemetry:
+		_, isNew = c.pending[frame.Sequence]
+		delete(c.pending, frame.Sequence)
+	}
+	if !isNew {
+		return
+	}
+
+	c.delivery = append(c.delivery, downlink)
+	if !c.delivering {
+		c.delivering = true
+		c.clock.Go(c.deliver)
+	}
+}
+
+// deliver hands the received downlinks to the operator in order, waiting
+// while the downlinks channel is full
+func (c *CommsDomain) deliver() {
+	c.deliverMu.Lock()
+	defer c.deliverMu.Unlock()
+
+	for {
+		c.mu.Lock()
+		if len(c.delivery) == 0 {
+			c.delivering = false
+			c.mu.Unlock()
+			return
+		}
+		downlink := c.delivery[0]
+		c.delivery = c.delivery[1:]
+		c.mu.Unlock()
+
+		select {
+		case <-c.done:
+			c.mu.Lock()
+			c.delivering = false
+			c.mu.Unlock()
+			return
+		default:
+		}
+
+		select {
+		case c.downlinks <- downlink:
+		case <-c.done:
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package domains
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestCommsDomain_Uplink(t *testing.T) {
+	rover := newRoverDomainMocked()
+	lightTime := 20 * time.Millisecond
+	durations := models.CommandDurationDto{ForwardMilliseconds: 10}
+
+	c := NewCommsDomain(&rover, models.CommsDto{LightTimeMilliseconds: int(lightTime / time.Millisecond)}, durations, NewRealClockDomain())
+	defer c.Close()
+
+	sentAt := time.Now()
+	c.Uplink([]string{"f", "f"})
+	c.Uplink([]string{"r", "T"})
+
+	// The acknowledgements report the location the batch is received at,
+	// the second one while the first batch may still be executing
+	wantAcks := []int{1, 2}
+	wantTelemetry := []models.DownlinkDto{
+		{BatchId: 1, Type: models.DownlinkTypeTelemetry, Location: models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 3}, Direction: models.DirectionNorth}},
+		{BatchId: 2, Type: models.DownlinkTypeTelemetry, Location: models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 3}, Direction: models.DirectionEast}, Error: "command 'T' unknown"},
+	}
+
+	gotAcks := []int{}
+	gotTelemetry := []models.DownlinkDto{}
+	for len(gotAcks)+len(gotTelemetry) < len(wantAcks)+len(wantTelemetry) {
+		downlink := <-c.Downlinks()
+		elapsed := time.Since(sentAt)
+		if elapsed < 2*lightTime {
+			t.Fatalf("CommsDomain downlink received after %v, want at least %v", elapsed, 2*lightTime)
+		}
+
+		if downlink.Type == models.DownlinkTypeAck {
+			gotAcks = append(gotAcks, downlink.BatchId)
+			continue
+		}
+		// The two forward commands take their duration on the rover
+		if want := 2*lightTime + 20*time.Millisecond; elapsed < want {
+			t.Fatalf("CommsDomain telemetry received after %v, want at least %v", elapsed, want)
+		}
+		gotTelemetry = append(gotTelemetry, downlink)
+	}
+
+	if !reflect.DeepEqual(gotAcks, wantAcks) {
+		t.Errorf("CommsDomain acknowledgements = %v, want %v", gotAcks, wantAcks)
+	}
+	if !reflect.DeepEqual(gotTelemetry, wantTelemetry) {
+		t.Errorf("CommsDomain telemetry = %v, want %v", gotTelemetry, wantTelemetry)
+	}
+}
+
+func TestCommsDomain_UplinkSlowOperator(t *testing.T) {
+	eventStore := NewEventStoreDomain(DefaultSnapshotInterval)
+	rover := newRoverDomainMocked()
+	rover.eventStore = eventStore
+
+	c := NewCommsDomain(&rover, models.CommsDto{LightTimeMilliseconds: 1}, models.CommandDurationDto{}, NewRealClockDomain())
+	defer c.Close()
+
+	// The batches send twice as many downlinks as the channel holds, none
+	// is read until every batch is executed, the last one sent once the
+	// channel is full
+	batches := DownlinkBufferSize + 1
+	for i := 0; i < batches-1; i++ {
+		c.Uplink([]string{"l"})
+	}
+	time.Sleep(20 * time.Millisecond)
+	c.Uplink([]string{"l"})
+
+	timeout := time.After(5 * time.Second)
+	for executed := 0; executed < batches; {
+		select {
+		case <-timeout:
+			t.Fatalf("CommsDomain executed %d batches while the downlinks are not read, want %d", executed, batches)
+		case <-time.After(time.Millisecond):
+		}
+
+		executed = 0
+		for _, event := range eventStore.Events(0, 0) {
+			if event.Type == models.EventTypeTurned {
+				executed++
+			}
+		}
+	}
+
+	for i := 0; i < 2*batches; i++ {
+		select {
+		case <-c.Downlinks():
+		case <-timeout:
+			t.Fatalf("CommsDomain downlinks received %d, want %d", i, 2*batches)
+		}
+	}
+}
+
+func TestCommsDomain_UplinkLossyLink(t *testing.T) {
+	eventStore := NewEventStoreDomain(DefaultSnapshotInterval)
+	rover := newRoverDomainMocked()
+	rover.eventStore = eventStore
+
+	c := NewCommsDomain(&rover, models.CommsDto{
+		LightTimeMilliseconds:         1,
+		RetransmitTimeoutMilliseconds: 10,
+		Link: models.LinkDto{
+			DropProbability:      0.2,
+			DuplicateProbability: 0.2,
+			ReorderProbability:   0.2,
+			CorruptProbability:   0.2,
+			Seed:                 7,
+		},
+	}, models.CommandDurationDto{}, NewRealClockDomain())
+	defer c.Close()
+
+	commands := []string{"f", "r", "f", "r", "f", "r", "f", "r", "f", "r"}
+	for _, cmd := range commands {
+		c.Uplink([]string{cmd})
+	}
+
+	telemetry := map[int]models.DownlinkDto{}
+	timeout := time.After(5 * time.Second)
+	for len(telemetry) < len(commands) {
+		select {
+		case downlink := <-c.Downlinks():
+			if downlink.Type != models.DownlinkTypeTelemetry {
+				continue
+			}
+			if _, ok := telemetry[downlink.BatchId]; ok {
+				t.Fatalf("CommsDomain telemetry of batch %d received twice", downlink.BatchId)
+			}
+			telemetry[downlink.BatchId] = downlink
+		case <-timeout:
+			t.Fatalf("CommsDomain telemetry received for %d batches, want %d", len(telemetry), len(commands))
+		}
+	}
+
+	// Every batch is executed once and in order
+	got := []string{}
+	for _, event := range eventStore.Events(0, 0) {
+		if event.Type == models.EventTypeMoved || event.Type == models.EventTypeTurned {
+			got = append(got, event.Command)
+		}
+	}
+	if !reflect.DeepEqual(got, commands) {
+		t.Errorf("CommsDomain executed commands = %v, want %v", got, commands)
+	}
+
+	want := models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 2}, Direction: models.DirectionEast}
+	if got := telemetry[len(commands)].Location; got != want {
+		t.Errorf("CommsDomain last telemetry location = %v, want %v", got, want)
+	}
+}
+
+func TestCommsDomain_UplinkSimulationClock(t *testing.T) {
+	clock := NewSimulationClockDomain(time.Millisecond)
+	rover := newRoverDomainMocked()
+	rover.clock = clock
+
+	c := NewCommsDomain(&rover, models.CommsDto{LightTimeMilliseconds: 1000}, models.CommandDurationDto{ForwardMilliseconds: 500}, clock)
+	defer c.Close()
+
+	c.Uplink([]string{"f", "f"})
+
+	// The batch arrives at 1s and is executed at 2s, its telemetry arriving
+	// at 3s. The retransmit sent at 2.1s is acknowledged again and the next
+	// one, due at 4.2s, finds the telemetry received.
+	if end, want := clock.Run(), 4200*time.Millisecond; end != want {
+		t.Errorf("SimulationClockDomain.Run() = %v, want %v", end, want)
+	}
+
+	want := []models.DownlinkDto{
+		{BatchId: 1, Type: models.DownlinkTypeAck, Location: models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorth}},
+		{BatchId: 1, Type: models.DownlinkTypeTelemetry, Location: models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 3}, Direction: models.DirectionNorth}},
+	}
+	got := []models.DownlinkDto{}
+	for len(got) < len(want) {
+		select {
+		case downlink := <-c.Downlinks():
+			got = append(got, downlink)
+		default:
+			t.Fatalf("CommsDomain downlinks = %v when the clock stops, want %v", got, want)
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CommsDomain downlinks = %v, want %v", got, want)
+	}
+}
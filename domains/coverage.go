@@ -0,0 +1,209 @@
+package domains
+
+import (
+	"fmt"
+
+	"github.com/mars-rover-go/models"
+)
+
+// CoveragePlannerDomain plans survey missions with a boustrophedon
+// decomposition: the region is swept column by column, the obstacles split
+// the columns into segments, and the segments connected from one column to
+// the next form the cells covered in a lawnmower pattern one after the other
+type CoveragePlannerDomain struct {
+	gridDomain     IGridDomain
+	obstacleDomain IObstacleDomain
+}
+
+func NewCoveragePlannerDomain(gridDomain IGridDomain, obstacleDomain IObstacleDomain) ICoveragePlannerDomain {
+	return &CoveragePlannerDomain{
+		gridDomain:     gridDomain,
+		obstacleDomain: obstacleDomain,
+	}
+}
+
+// columnSegment is a run of reachable points of a column, from yMin to yMax
+type columnSegment struct {
+	x    int
+	yMin int
+	yMax int
+	cell int
+}
+
+func (c *CoveragePlannerDomain) PlanCoverage(from models.LocationDto, region models.RegionDto) (models.CoveragePlanDto, error) {
+	if !c.gridDomain.IsPointInGrid(region.Min) || !c.gridDomain.IsPointInGrid(region.Max) {
+		return models.CoveragePlanDto{}, fmt.Errorf("region is out the grid")
+	}
+	if region.Min.XPoint > region.Max.XPoint || region.Min.YPoint > region.Max.YPoint {
+		return models.CoveragePlanDto{}, fmt.Errorf("region min point %d %d greater than max point", region.Min.XPoint, region.Min.YPoint)
+	}
+
+	syncObstacles(c.obstacleDomain)
+
+	// The full searches are shared by the cell choices and the legs starting
+	// from the same location, the other legs only search up to their target
+	canEnter := freeOf(c.obstacleDomain)
+	searches := map[models.LocationDto]*commandSearch{}
+	searchFrom := func(location models.LocationDto) *commandSearch {
+		search, ok := searches[location]
+		if !ok {
+			search = searchLocations(c.gridDomain, location, canEnter, nil)
+			searches[location] = search
+		}
+		return search
+	}
+	search := searchFrom(from)
+
+	plan := models.CoveragePlanDto{Location: from, Visits: []models.PointDto{}, Unreachable: []models.PointDto{}}
+	reachable := map[models.PointDto]bool{}
+	for _, location := range search.reached {
+		reachable[location.Point] = true
+	}
+	for x := region.Min.XPoint; x <= region.Max.XPoint; x++ {
+		for y := region.Min.YPoint; y <= region.Max.YPoint; y++ {
+			point := models.PointDto{XPoint: x, YPoint: y}
+			if !reachable[point] && !c.obstacleDomain.IsObstacle(point) {
+				plan.Unreachable = append(plan.Unreachable, point)
+			}
+		}
+	}
+
+	cells := decomposeRegion(region, reachable)
+	plan.Cells = len(cells)
+
+	visited := map[models.PointDto]bool{}
+	visit := func(point models.PointDto) {
+		if !visited[point] && isInRegion(region, point) {
+			visited[point] = true
+			plan.Visits = append(plan.Visits, point)
+		}
+	}
+	visit(from.Point)
+
+	covered := make([]bool, len(cells))
+	for range cells {
+		cell := nearestCell(searchFrom(plan.Location), cells, covered)
+		covered[cell] = true
+
+		for _, target := range sweepCell(cells[cell], plan.Location.Point) {
+			if visited[target] {
+				continue
+			}
+
+			legSearch, ok := searches[plan.Location]
+			if !ok {
+				legSearch = searchLocationsTo(c.gridDomain, plan.Location, canEnter, target)
+			}
+			end, _ := legSearch.nearest(func(point models.PointDto) bool { return point == target })
+			for _, cmd := range legSearch.commandsTo(end) {
+				plan.Location, _ = nextLocation(c.gridDomain, plan.Location, cmd)
+				plan.Commands = append(plan.Commands, cmd)
+				visit(plan.Location.Point)
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// nearestCell returns the cell not covered yet with the point reached first
+// by the search
+func nearestCell(search *commandSearch, cells [][]columnSegment, covered []bool) int {
+	cellOf := map[models.PointDto]int{}
+	for i, segments := range cells {
+		for _, segment := range segments {
+			for y := segment.yMin; y <= segment.yMax; y++ {
+				cellOf[models.PointDto{XPoint: segment.x, YPoint: y}] = i
+			}
+		}
+	}
+
+	for _, location := range search.reached {
+		if cell, ok := cellOf[location.Point]; ok && !covered[cell] {
+			return cell
+		}
+	}
+
+	// Every cell is reachable, the search always finds one
+	for i := range cells {
+		if !covered[i] {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// decomposeRegion splits the reachable points of the region into cells. A
+// column segment continues the cell of the previous column segment it
+// touches when they only touch each other, otherwise the obstacles split or
+// merge the area and the segment starts a new cell.
+func decomposeRegion(region models.RegionDto, reachable map[models.PointDto]bool) [][]columnSegment {
+	cells := [][]columnSegment{}
+	previous := []columnSegment{}
+
+	for x := region.Min.XPoint; x <= region.Max.XPoint; x++ {
+		current := []columnSegment{}
+		for y := region.Min.YPoint; y <= region.Max.YPoint; y++ {
+			if !reachable[models.PointDto{XPoint: x, YPoint: y}] {
+				continue
+			}
+			if n := len(current); n > 0 && current[n-1].yMax == y-1 {
+				current[n-1].yMax = y
+				continue
+			}
+			current = append(current, columnSegment{x: x, yMin: y, yMax: y})
+		}
+
+		for i := range current {
+			touched := touchingSegments(current[i], previous)
+			if len(touched) == 1 && len(touchingSegments(touched[0], current)) == 1 {
+				current[i].cell = touched[0].cell
+			} else {
+				current[i].cell = len(cells)
+				cells = append(cells, nil)
+			}
+			cells[current[i].cell] = append(cells[current[i].cell], current[i])
+		}
+
+		previous = current
+	}
+
+	return cells
+}
+
+func touchingSegments(segment columnSegment, segments []columnSegment) []columnSegment {
+	touching := []columnSegment{}
+	for _, s := range segments {
+		if s.yMin <= segment.yMax && segment.yMin <= s.yMax {
+			touching = append(touching, s)
+		}
+	}
+
+	return touching
+}
+
+// sweepCell returns the cell points column by column, going up and down in
+// turn, starting from the column end nearest to the point
+func sweepCell(segments []columnSegment, from models.PointDto) []models.PointDto {
+	points := []models.PointDto{}
+	up := absInt(from.YPoint-segments[0].yMin) <= absInt(from.YPoint-segments[0].yMax)
+
+	for _, segment := range segments {
+		for i := 0; i <= segment.yMax-segment.yMin; i++ {
+			y := segment.yMin + i
+			if !up {
+				y = segment.yMax - i
+			}
+			points = append(points, models.PointDto{XPoint: segment.x, YPoint: y})
+		}
+		up = !up
+	}
+
+	return points
+}
+
+func isInRegion(region models.RegionDto, point models.PointDto) bool {
+	return point.XPoint >= region.Min.XPoint && point.XPoint <= region.Max.XPoint &&
+		point.YPoint >= region.Min.YPoint && point.YPoint <= region.Max.YPoint
+}
@@ -0,0 +1,113 @@
+package domains
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestCoveragePlannerDomain_PlanCoverage(t *testing.T) {
+	tests := []struct {
+		name            string
+		obstacles       []models.ObstacleDto
+		region          models.RegionDto
+		wantUnreachable []models.PointDto
+		wantCells       int
+		wantErr         bool
+	}{
+		{
+			name:            "Region free of obstacles",
+			region:          models.RegionDto{Min: models.PointDto{XPoint: 0, YPoint: 0}, Max: models.PointDto{XPoint: 4, YPoint: 3}},
+			wantUnreachable: []models.PointDto{},
+			wantCells:       1,
+		},
+		{
+			name: "Region split around an obstacle",
+			obstacles: []models.ObstacleDto{
+				{Point: models.PointDto{XPoint: 2, YPoint: 2}},
+			},
+			region:          models.RegionDto{Min: models.PointDto{XPoint: 0, YPoint: 0}, Max: models.PointDto{XPoint: 4, YPoint: 4}},
+			wantUnreachable: []models.PointDto{},
+			wantCells:       4,
+		},
+		{
+			name: "Point enclosed by obstacles",
+			obstacles: []models.ObstacleDto{
+				{Point: models.PointDto{XPoint: 4, YPoint: 5}},
+				{Point: models.PointDto{XPoint: 6, YPoint: 5}},
+				{Point: models.PointDto{XPoint: 5, YPoint: 4}},
+				{Point: models.PointDto{XPoint: 5, YPoint: 6}},
+			},
+			region:          models.RegionDto{Min: models.PointDto{XPoint: 3, YPoint: 3}, Max: models.PointDto{XPoint: 7, YPoint: 7}},
+			wantUnreachable: []models.PointDto{{XPoint: 5, YPoint: 5}},
+			wantCells:       4,
+		},
+		{
+			name:    "Region min greater than max",
+			region:  models.RegionDto{Min: models.PointDto{XPoint: 4, YPoint: 0}, Max: models.PointDto{XPoint: 2, YPoint: 3}},
+			wantErr: true,
+		},
+		{
+			name:    "Region out the grid",
+			region:  models.RegionDto{Min: models.PointDto{XPoint: 0, YPoint: 0}, Max: models.PointDto{XPoint: 11, YPoint: 3}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rover := newRoverDomainMocked()
+			rover.obstacleDomain = &ObstacleDomain{obstacles: tt.obstacles}
+			c := NewCoveragePlannerDomain(rover.gridDomain, rover.obstacleDomain)
+
+			got, err := c.PlanCoverage(rover.Location(), tt.region)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CoveragePlannerDomain.PlanCoverage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got.Unreachable, tt.wantUnreachable) {
+				t.Errorf("CoveragePlannerDomain.PlanCoverage() unreachable = %v, want %v", got.Unreachable, tt.wantUnreachable)
+			}
+			if got.Cells != tt.wantCells {
+				t.Errorf("CoveragePlannerDomain.PlanCoverage() cells = %v, want %v", got.Cells, tt.wantCells)
+			}
+
+			// The rover executes the commands and passes on every reachable point of the region
+			plan, err := rover.Plan(got.Commands)
+			if err != nil {
+				t.Fatalf("RoverDomain.Plan() error = %v", err)
+			}
+			if plan.Location != got.Location {
+				t.Errorf("CoveragePlannerDomain.PlanCoverage() location = %v, want %v", got.Location, plan.Location)
+			}
+
+			passed := map[models.PointDto]bool{rover.Location().Point: true}
+			for _, event := range plan.Events {
+				passed[event.Location.Point] = true
+			}
+			unreachable := map[models.PointDto]bool{}
+			for _, point := range tt.wantUnreachable {
+				unreachable[point] = true
+			}
+
+			points := 0
+			for x := tt.region.Min.XPoint; x <= tt.region.Max.XPoint; x++ {
+				for y := tt.region.Min.YPoint; y <= tt.region.Max.YPoint; y++ {
+					point := models.PointDto{XPoint: x, YPoint: y}
+					if rover.obstacleDomain.IsObstacle(point) || unreachable[point] {
+						continue
+					}
+					points++
+					if !passed[point] {
+						t.Errorf("CoveragePlannerDomain.PlanCoverage() point %v not visited", point)
+					}
+				}
+			}
+			if len(got.Visits) != points {
+				t.Errorf("CoveragePlannerDomain.PlanCoverage() visits = %v, want %v", len(got.Visits), points)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package domains
 
-import "github.com/mars-rover-go/models"
+import (
+	"context"
+	"time"
+
+	"github.com/mars-rover-go/models"
+)
 
 type IRoverDomain interface {
 	// ExecuteCommands executes the mars rover commands.
@@ -11,14 +16,218 @@ type IRoverDomain interface {
 	//  - r: right
 	// Returns the rover location (x, y and direction).
 	ExecuteCommands(commands []string) (models.LocationDto, error)
+	// ExecuteCommandsContext executes the commands until the context is done.
+	// A cancelled batch stops at the last completed command with a *BatchCancelledError.
+	ExecuteCommandsContext(ctx context.Context, commands []string) (models.LocationDto, error)
+	// ExecuteTimedCommands executes the commands, each one completing after its
+	// duration, until the context is done.
+	// A cancelled batch stops at the last completed command with a *BatchCancelledError.
+	ExecuteTimedCommands(ctx context.Context, commands []string, durations models.CommandDurationDto) (models.LocationDto, error)
+	// ExecutePlannedCommands executes the commands planned from the rover
+	// location as ExecuteTimedCommands does. No other batch runs between the
+	// planning and the execution, so the rover is still on the planned location.
+	ExecutePlannedCommands(ctx context.Context, plan func(from models.LocationDto) ([]string, error), durations models.CommandDurationDto) (models.LocationDto, error)
+	// Location returns the current rover location
+	Location() models.LocationDto
+	// Reset lands the rover again on the location
+	Reset(location models.LocationDto) error
+	// Plan returns the events the commands would produce and the resulting
+	// location, without moving the rover
+	Plan(commands []string) (models.PlanDto, error)
 }
 
 type IGridDomain interface {
 	// IsPointInGrid checks if the point is in the grid
 	IsPointInGrid(point models.PointDto) bool
+	// WrapPoint wraps a point out of the grid to the opposite edge.
+	// Returns false if the grid has no wrapping.
+	WrapPoint(point models.PointDto) (models.PointDto, bool)
+	// HasDiagonals checks if the rover heads in eight directions, turning
+	// 45 degrees instead of 90
+	HasDiagonals() bool
 }
 
 type IObstacleDomain interface {
 	// IsObstacle checks if the point is an obstacle
 	IsObstacle(point models.PointDto) bool
 }
+
+// IDirectionalObstacleDomain is an obstacle domain where entering a point
+// depends on the point the rover comes from
+type IDirectionalObstacleDomain interface {
+	IObstacleDomain
+	// IsObstacleFrom checks if the point is an obstacle for the rover coming
+	// from the neighbour point
+	IsObstacleFrom(from models.PointDto, point models.PointDto) bool
+}
+
+// ISyncedObstacleDomain is an obstacle domain built from the rover events,
+// the planners syncing it before planning
+type ISyncedObstacleDomain interface {
+	IObstacleDomain
+	// Sync updates the obstacles with the rover events stored since the last sync
+	Sync()
+}
+
+type IObstacleMapDomain interface {
+	IObstacleDomain
+	// Add adds the obstacles, ignoring the points already obstacles
+	Add(obstacles ...models.ObstacleDto)
+	// Remove removes the obstacles, ignoring the points not obstacles
+	Remove(obstacles ...models.ObstacleDto)
+	// Obstacles returns the obstacles
+	Obstacles() []models.ObstacleDto
+}
+
+type IKnownMapDomain interface {
+	// IsObstacle checks if the point is a known obstacle, Sync observes the
+	// rover events stored since the last sync
+	ISyncedObstacleDomain
+	// Observe updates the map from the rover events: the sensor scans from every
+	// location reached, and the obstacles encountered become known
+	Observe(events ...models.EventDto)
+	// Sense scans the points in the sensor range and field of view from the
+	// location, the points hidden behind an obstacle are not seen
+	Sense(location models.LocationDto)
+	// IsExplored checks if the point was sensed
+	IsExplored(point models.PointDto) bool
+	// KnownObstacles returns the obstacles found so far
+	KnownObstacles() []models.ObstacleDto
+	// ExploredPoints returns the number of points sensed
+	ExploredPoints() int
+}
+
+type IExplorerDomain interface {
+	// Explore moves the rover to the unexplored points, replanning after each
+	// batch, until the coverage target (from 0 to 1) or the command budget is
+	// reached, or no unexplored point is reachable. A budget of 0 is unlimited.
+	// Returns the coverage after each batch.
+	Explore(strategy models.ExplorationStrategy, coverageTarget float64, budget int) ([]models.ExplorationStepDto, error)
+}
+
+type ICoveragePlannerDomain interface {
+	// PlanCoverage returns the commands visiting every reachable point of the
+	// region from the location, in a lawnmower pattern around the obstacles
+	PlanCoverage(from models.LocationDto, region models.RegionDto) (models.CoveragePlanDto, error)
+}
+
+type INavigatorDomain interface {
+	// ExecuteTimedCommands executes the commands on the rover, each one
+	// completing after its duration. With the auto retreat the rover
+	// retreats after an obstacle aborts the batch, the error reporting both.
+	ExecuteTimedCommands(ctx context.Context, commands []string, durations models.CommandDurationDto) (models.LocationDto, error)
+	// ReturnHome drives the rover back to its starting location
+	ReturnHome(ctx context.Context, durations models.CommandDurationDto) (models.LocationDto, error)
+	// Retreat drives the rover to the nearest safe zone, or back to its
+	// starting location when there is none
+	Retreat(ctx context.Context, durations models.CommandDurationDto) (models.LocationDto, error)
+}
+
+type IMapGeneratorDomain interface {
+	// Generate returns a configuration with the grid and the obstacles of a
+	// map generated from the settings
+	Generate(settings models.MapGenerationDto) (models.ConfigurationDto, error)
+}
+
+type IMapAnalysisDomain interface {
+	// Analyze returns the regions of the map, the points reachable from the
+	// start point and the choke points of each region
+	Analyze(start models.PointDto) (models.MapAnalysisDto, error)
+}
+
+type IMissionPlannerDomain interface {
+	// PlanMission returns the tour visiting the sites from the location with
+	// the commands of each leg, minimizing the objective
+	PlanMission(from models.LocationDto, sites []models.PointDto, objective models.MissionObjective) (models.MissionPlanDto, error)
+}
+
+type IEventStoreDomain interface {
+	// Append stores the rover events, taking a snapshot every snapshot interval
+	Append(events ...models.EventDto)
+	// Events returns the rover events with a sequence greater than fromSequence
+	Events(roverId int, fromSequence int) []models.EventDto
+	// LocationAt rebuilds the rover location after the event with the given sequence
+	LocationAt(roverId int, sequence int) (models.LocationDto, error)
+	// LocationAfterBatch rebuilds the rover location after the given batch
+	LocationAfterBatch(roverId int, batch int) (models.LocationDto, error)
+}
+
+type IEventBusDomain interface {
+	// Publish delivers the event to every subscriber
+	Publish(event models.EventDto)
+	// Subscribe registers an asynchronous subscriber with a bounded buffer and
+	// the policy applied when the buffer is full. With the block policy the
+	// publisher waits on the subscriber, which must not wait on the publisher
+	// in return: a rover subscriber can read the rover state but must not
+	// execute batches on it.
+	Subscribe(subscriber ISubscriber, bufferSize int, policy models.DeliveryPolicy) ISubscription
+	// Close stops the bus, waiting for the subscribers to drain their buffers
+	Close()
+}
+
+type ISubscriber interface {
+	// OnEvent is called for every event published on the bus
+	OnEvent(event models.EventDto)
+}
+
+type ISubscription interface {
+	// Dropped returns the number of events discarded by the drop policy
+	Dropped() int
+	// Unsubscribe removes the subscriber from the bus
+	Unsubscribe()
+}
+
+type ICommsDomain interface {
+	// Uplink queues a command batch to the rover, delivered after the light time.
+	// Returns the batch id reported in the downlinks.
+	Uplink(commands []string) int
+	// Downlinks returns the acknowledgements and telemetry sent by the rover,
+	// received after the light time
+	Downlinks() <-chan models.DownlinkDto
+	// Close stops the communications, discarding the messages in flight
+	Close()
+}
+
+type ILinkDomain interface {
+	// Transmit sends the encoded frame, calling deliver when it reaches the other end.
+	// The frame may never be delivered.
+	Transmit(frame []byte, deliver func(frame []byte))
+	// Close stops the link, discarding the frames in flight
+	Close()
+}
+
+type IEnvelopeDomain interface {
+	// Sign wraps the commands in an envelope signed with the shared key
+	Sign(commands []string) models.EnvelopeDto
+	// Verify checks the envelope signature and rejects stale or replayed envelopes
+	// with an *EnvelopeError
+	Verify(envelope models.EnvelopeDto) error
+}
+
+type IClockDomain interface {
+	// Now returns the time elapsed since the clock started
+	Now() time.Duration
+	// Sleep waits for the duration, returning the context error if it is done
+	Sleep(ctx context.Context, d time.Duration) error
+	// AfterFunc calls the function once the duration has elapsed.
+	// Functions due at the same time are called in the order they were scheduled.
+	AfterFunc(d time.Duration, fn func())
+	// Go starts a task allowed to sleep on the clock
+	Go(task func())
+}
+
+type ISimulationDomain interface {
+	// Submit starts executing the command batch at the current simulated time,
+	// each command completing after its duration.
+	// The batch waits for the previous ones to complete.
+	Submit(commands []string)
+	// Abort cancels the batches submitted so far, the running command does
+	// not complete
+	Abort()
+	// RunUntil advances the clock to the simulated time
+	RunUntil(t time.Duration)
+	// Run advances the clock until every batch is completed and nothing is scheduled
+	Run() time.Duration
+	// Results returns the outcome of the completed batches, in completion order
+	Results() []models.SimulationResultDto
+}
@@ -0,0 +1,119 @@
+package domains
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mars-rover-go/models"
+)
+
+const DefaultEnvelopeMaxAge = 60 * time.Second
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrStaleEnvelope    = errors.New("stale envelope")
+	ErrReplayedEnvelope = errors.New("replayed envelope")
+)
+
+// EnvelopeError reports a rejected envelope.
+// Use errors.Is with ErrInvalidSignature, ErrStaleEnvelope or ErrReplayedEnvelope to get the reason.
+type EnvelopeError struct {
+	Sequence uint64
+	Err      error
+}
+
+func (e *EnvelopeError) Error() string {
+	return fmt.Sprintf("envelope %d rejected: %v", e.Sequence, e.Err)
+}
+
+func (e *EnvelopeError) Unwrap() error {
+	return e.Err
+}
+
+type EnvelopeDomain struct {
+	key    []byte
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu       sync.Mutex
+	sequence uint64
+	// seen holds the timestamps of the sequences accepted within the max age
+	seen map[uint64]int64
+}
+
+func NewEnvelopeDomain(security models.SecurityDto) IEnvelopeDomain {
+	maxAge := time.Duration(security.MaxAgeSeconds) * time.Second
+	if maxAge <= 0 {
+		maxAge = DefaultEnvelopeMaxAge
+	}
+
+	return &EnvelopeDomain{
+		key:    []byte(security.Key),
+		maxAge: maxAge,
+		now:    time.Now,
+		// Starts from the clock so sequences stay unique across restarts
+		sequence: uint64(time.Now().UnixNano()),
+		seen:     map[uint64]int64{},
+	}
+}
+
+func (e *EnvelopeDomain) Sign(commands []string) models.EnvelopeDto {
+	e.mu.Lock()
+	e.sequence++
+	sequence := e.sequence
+	e.mu.Unlock()
+
+	envelope := models.EnvelopeDto{
+		Sequence:  sequence,
+		Timestamp: e.now().UnixNano() / int64(time.Millisecond),
+		Commands:  commands,
+	}
+	envelope.Signature = hex.EncodeToString(e.signature(envelope))
+
+	return envelope
+}
+
+func (e *EnvelopeDomain) Verify(envelope models.EnvelopeDto) error {
+	signature, err := hex.DecodeString(envelope.Signature)
+	if err != nil || !hmac.Equal(signature, e.signature(envelope)) {
+		return &EnvelopeError{Sequence: envelope.Sequence, Err: ErrInvalidSignature}
+	}
+
+	now := e.now().UnixNano() / int64(time.Millisecond)
+	maxAge := e.maxAge.Milliseconds()
+	if envelope.Timestamp < now-maxAge || envelope.Timestamp > now+maxAge {
+		return &EnvelopeError{Sequence: envelope.Sequence, Err: ErrStaleEnvelope}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Envelopes older than the max age are stale, no need to remember them
+	for sequence, timestamp := range e.seen {
+		if timestamp < now-maxAge {
+			delete(e.seen, sequence)
+		}
+	}
+
+	if _, ok := e.seen[envelope.Sequence]; ok {
+		return &EnvelopeError{Sequence: envelope.Sequence, Err: ErrReplayedEnvelope}
+	}
+	e.seen[envelope.Sequence] = envelope.Timestamp
+
+	return nil
+}
+
+func (e *EnvelopeDomain) signature(envelope models.EnvelopeDto) []byte {
+	commands, _ := json.Marshal(envelope.Commands)
+
+	mac := hmac.New(sha256.New, e.key)
+	fmt.Fprintf(mac, "%d\n%d\n%s", envelope.Sequence, envelope.Timestamp, commands)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,78 @@
+package domains
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestEnvelopeDomain_Verify(t *testing.T) {
+	now := time.Date(2021, 5, 14, 10, 0, 0, 0, time.UTC)
+
+	signer := newEnvelopeDomainMocked("secret", now)
+	envelope := signer.Sign([]string{"f", "r"})
+
+	tampered := signer.Sign([]string{"f"})
+	tampered.Commands = []string{"b"}
+
+	stale := newEnvelopeDomainMocked("secret", now.Add(-2*time.Minute)).Sign([]string{"f"})
+	wrongKey := newEnvelopeDomainMocked("other", now).Sign([]string{"f"})
+
+	verifier := newEnvelopeDomainMocked("secret", now)
+
+	tests := []struct {
+		name     string
+		envelope models.EnvelopeDto
+		wantErr  error
+	}{
+		{
+			name:     "Envelope ok",
+			envelope: envelope,
+			wantErr:  nil,
+		},
+		{
+			name:     "Envelope replayed",
+			envelope: envelope,
+			wantErr:  ErrReplayedEnvelope,
+		},
+		{
+			name:     "Envelope tampered",
+			envelope: tampered,
+			wantErr:  ErrInvalidSignature,
+		},
+		{
+			name:     "Envelope signed with another key",
+			envelope: wrongKey,
+			wantErr:  ErrInvalidSignature,
+		},
+		{
+			name:     "Envelope stale",
+			envelope: stale,
+			wantErr:  ErrStaleEnvelope,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifier.Verify(tt.envelope)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("EnvelopeDomain.Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var envelopeErr *EnvelopeError
+			if tt.wantErr != nil && !errors.As(err, &envelopeErr) {
+				t.Errorf("EnvelopeDomain.Verify() error = %T, want *EnvelopeError", err)
+			}
+		})
+	}
+}
+
+func newEnvelopeDomainMocked(key string, now time.Time) *EnvelopeDomain {
+	envelopeDomain := NewEnvelopeDomain(models.SecurityDto{Key: key, MaxAgeSeconds: 60}).(*EnvelopeDomain)
+	envelopeDomain.now = func() time.Time {
+		return now
+	}
+
+	return envelopeDomain
+}
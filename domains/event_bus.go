@@ -0,0 +1,136 @@
+package domains
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/mars-rover-go/models"
+)
+
+// SubscriberFunc adapts a function to the ISubscriber interface
+type SubscriberFunc func(event models.EventDto)
+
+func (f SubscriberFunc) OnEvent(event models.EventDto) {
+	f(event)
+}
+
+type EventBusDomain struct {
+	mu            sync.RWMutex
+	closed        bool
+	subscriptions map[*subscription]struct{}
+	wg            sync.WaitGroup
+}
+
+func NewEventBusDomain() IEventBusDomain {
+	return &EventBusDomain{
+		subscriptions: map[*subscription]struct{}{},
+	}
+}
+
+func (b *EventBusDomain) Publish(event models.EventDto) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return
+	}
+
+	for s := range b.subscriptions {
+		s.deliver(event)
+	}
+}
+
+func (b *EventBusDomain) Subscribe(subscriber ISubscriber, bufferSize int, policy models.DeliveryPolicy) ISubscription {
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+
+	s := &subscription{
+		bus:        b,
+		subscriber: subscriber,
+		policy:     policy,
+		events:     make(chan models.EventDto, bufferSize),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(s.events)
+		return s
+	}
+
+	b.subscriptions[s] = struct{}{}
+	b.wg.Add(1)
+	go s.run(&b.wg)
+
+	return s
+}
+
+func (b *EventBusDomain) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+
+	b.closed = true
+	for s := range b.subscriptions {
+		close(s.events)
+		delete(b.subscriptions, s)
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+}
+
+func (b *EventBusDomain) unsubscribe(s *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscriptions[s]; !ok {
+		return
+	}
+
+	close(s.events)
+	delete(b.subscriptions, s)
+}
+
+type subscription struct {
+	bus        *EventBusDomain
+	subscriber ISubscriber
+	policy     models.DeliveryPolicy
+	events     chan models.EventDto
+	dropped    int64
+}
+
+func (s *subscription) Dropped() int {
+	return int(atomic.LoadInt64(&s.dropped))
+}
+
+// Unsubscribe removes the subscriber from the bus. It must not be called
+// from OnEvent while a publisher is blocked on the subscriber buffer.
+func (s *subscription) Unsubscribe() {
+	s.bus.unsubscribe(s)
+}
+
+func (s *subscription) deliver(event models.EventDto) {
+	if s.policy == models.DeliveryPolicyBlock {
+		s.events <- event
+		return
+	}
+
+	select {
+	case s.events <- event:
+	default:
+		atomic.AddInt64(&s.dropped, 1)
+	}
+}
+
+func (s *subscription) run(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for event := range s.events {
+		s.subscriber.OnEvent(event)
+	}
+}
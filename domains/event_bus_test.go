@@ -0,0 +1,103 @@
+package domains
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestEventBusDomain_Subscribe(t *testing.T) {
+	type args struct {
+		bufferSize int
+		policy     models.DeliveryPolicy
+	}
+
+	tests := []struct {
+		name        string
+		args        args
+		gated       bool
+		published   int
+		wantEvents  []int
+		wantDropped int
+	}{
+		{
+			name: "Block policy delivers every event",
+			args: args{
+				bufferSize: 1,
+				policy:     models.DeliveryPolicyBlock,
+			},
+			gated:       false,
+			published:   5,
+			wantEvents:  []int{1, 2, 3, 4, 5},
+			wantDropped: 0,
+		},
+		{
+			name: "Drop policy discards events on full buffer",
+			args: args{
+				bufferSize: 2,
+				policy:     models.DeliveryPolicyDrop,
+			},
+			gated:       true,
+			published:   5,
+			wantEvents:  []int{1, 2, 3},
+			wantDropped: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewEventBusDomain()
+
+			started := make(chan struct{})
+			gate := make(chan struct{})
+			if !tt.gated {
+				close(gate)
+			}
+
+			got := []int{}
+			subscription := b.Subscribe(SubscriberFunc(func(event models.EventDto) {
+				if event.Sequence == 1 {
+					close(started)
+					<-gate
+				}
+				got = append(got, event.Sequence)
+			}), tt.args.bufferSize, tt.args.policy)
+
+			b.Publish(models.EventDto{Sequence: 1})
+			<-started
+			for i := 2; i <= tt.published; i++ {
+				b.Publish(models.EventDto{Sequence: i})
+			}
+
+			if tt.gated {
+				close(gate)
+			}
+			b.Close()
+
+			if !reflect.DeepEqual(got, tt.wantEvents) {
+				t.Errorf("EventBusDomain events = %v, want %v", got, tt.wantEvents)
+			}
+			if dropped := subscription.Dropped(); dropped != tt.wantDropped {
+				t.Errorf("EventBusDomain dropped = %v, want %v", dropped, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestEventBusDomain_Unsubscribe(t *testing.T) {
+	b := NewEventBusDomain()
+
+	got := 0
+	subscription := b.Subscribe(SubscriberFunc(func(event models.EventDto) {
+		got++
+	}), 10, models.DeliveryPolicyBlock)
+
+	b.Publish(models.EventDto{Sequence: 1})
+	subscription.Unsubscribe()
+	b.Publish(models.EventDto{Sequence: 2})
+	b.Close()
+
+	if got != 1 {
+		t.Errorf("EventBusDomain events = %v, want %v", got, 1)
+	}
+}
@@ -0,0 +1,140 @@
+package domains
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/mars-rover-go/models"
+)
+
+const DefaultSnapshotInterval = 50
+
+type EventStoreDomain struct {
+	mu               sync.RWMutex
+	snapshotInterval int
+	events           map[int][]models.EventDto
+	snapshots        map[int][]models.SnapshotDto
+}
+
+func NewEventStoreDomain(snapshotInterval int) IEventStoreDomain {
+	if snapshotInterval <= 0 {
+		snapshotInterval = DefaultSnapshotInterval
+	}
+
+	return &EventStoreDomain{
+		snapshotInterval: snapshotInterval,
+		events:           map[int][]models.EventDto{},
+		snapshots:        map[int][]models.SnapshotDto{},
+	}
+}
+
+func (s *EventStoreDomain) Append(events ...models.EventDto) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, event := range events {
+		roverEvents := append(s.events[event.RoverId], event)
+		s.events[event.RoverId] = roverEvents
+
+		if len(roverEvents)%s.snapshotInterval == 0 {
+			s.takeSnapshot(event)
+		}
+	}
+}
+
+func (s *EventStoreDomain) Events(roverId int, fromSequence int) []models.EventDto {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	roverEvents := s.events[roverId]
+
+	return append([]models.EventDto{}, roverEvents[s.eventIndex(roverId, fromSequence):]...)
+}
+
+func (s *EventStoreDomain) LocationAt(roverId int, sequence int) (models.LocationDto, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.rebuild(roverId, func(event models.EventDto) bool {
+		return event.Sequence <= sequence
+	})
+}
+
+func (s *EventStoreDomain) LocationAfterBatch(roverId int, batch int) (models.LocationDto, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.rebuild(roverId, func(event models.EventDto) bool {
+		return event.Batch <= batch
+	})
+}
+
+// rebuild replays the rover events matching the filter starting from the
+// latest snapshot matching it. Events are ordered by sequence and batch,
+// so the filter matches a prefix of the stream and of the snapshots.
+func (s *EventStoreDomain) rebuild(roverId int, filter func(event models.EventDto) bool) (models.LocationDto, error) {
+	var location models.LocationDto
+	fromSequence := 0
+	found := false
+
+	snapshots := s.snapshots[roverId]
+	matching := sort.Search(len(snapshots), func(i int) bool {
+		return !filter(models.EventDto{Sequence: snapshots[i].Sequence, Batch: snapshots[i].Batch})
+	})
+	if matching > 0 {
+		snapshot := snapshots[matching-1]
+		location = snapshot.Location
+		fromSequence = snapshot.Sequence
+		found = true
+	}
+
+	roverEvents := s.events[roverId]
+	for _, event := range roverEvents[s.eventIndex(roverId, fromSequence):] {
+		if !filter(event) {
+			break
+		}
+
+		location = applyEvent(location, event)
+		found = true
+	}
+
+	if !found {
+		return location, fmt.Errorf("no events found for rover %d", roverId)
+	}
+
+	return location, nil
+}
+
+// eventIndex returns the index of the first rover event with a sequence
+// greater than fromSequence, the events being ordered by sequence
+func (s *EventStoreDomain) eventIndex(roverId int, fromSequence int) int {
+	roverEvents := s.events[roverId]
+
+	return sort.Search(len(roverEvents), func(i int) bool {
+		return roverEvents[i].Sequence > fromSequence
+	})
+}
+
+func (s *EventStoreDomain) takeSnapshot(event models.EventDto) {
+	location, _ := s.rebuild(event.RoverId, func(e models.EventDto) bool {
+		return e.Sequence <= event.Sequence
+	})
+
+	s.snapshots[event.RoverId] = append(s.snapshots[event.RoverId], models.SnapshotDto{
+		RoverId:  event.RoverId,
+		Sequence: event.Sequence,
+		Batch:    event.Batch,
+		Location: location,
+	})
+}
+
+// applyEvent returns the rover location resulting from the event
+func applyEvent(location models.LocationDto, event models.EventDto) models.LocationDto {
+	switch event.Type {
+	case models.EventTypeLanded, models.EventTypeMoved, models.EventTypeTurned, models.EventTypeEdgeWrapped:
+		return event.Location
+	}
+
+	return location
+}
@@ -0,0 +1,191 @@
+package domains
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestEventStoreDomain_LocationAfterBatch(t *testing.T) {
+	type args struct {
+		roverId int
+		batch   int
+	}
+
+	eventStore := newEventStoreDomainMocked()
+
+	tests := []struct {
+		name    string
+		s       IEventStoreDomain
+		args    args
+		want    models.LocationDto
+		wantErr bool
+	}{
+		{
+			name: "Rover unknown",
+			s:    eventStore,
+			args: args{
+				roverId: 2,
+				batch:   1,
+			},
+			want:    models.LocationDto{},
+			wantErr: true,
+		},
+		{
+			name: "Location after landing",
+			s:    eventStore,
+			args: args{
+				roverId: 1,
+				batch:   0,
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorth},
+			wantErr: false,
+		},
+		{
+			name: "Location after first batch",
+			s:    eventStore,
+			args: args{
+				roverId: 1,
+				batch:   1,
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 3}, Direction: models.DirectionEast},
+			wantErr: false,
+		},
+		{
+			name: "Location after batch aborted by obstacle",
+			s:    eventStore,
+			args: args{
+				roverId: 1,
+				batch:   2,
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 5}, Direction: models.DirectionNorth},
+			wantErr: false,
+		},
+		{
+			name: "Location after wrapping",
+			s:    eventStore,
+			args: args{
+				roverId: 1,
+				batch:   3,
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 10, YPoint: 5}, Direction: models.DirectionWest},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.LocationAfterBatch(tt.args.roverId, tt.args.batch)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EventStoreDomain.LocationAfterBatch() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EventStoreDomain.LocationAfterBatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventStoreDomain_Events(t *testing.T) {
+	eventStore := newEventStoreDomainMocked()
+
+	tests := []struct {
+		name         string
+		roverId      int
+		fromSequence int
+		want         []models.EventType
+	}{
+		{
+			name:         "Events after a sequence",
+			roverId:      1,
+			fromSequence: 8,
+			want: []models.EventType{
+				models.EventTypeObstacleEncountered,
+				models.EventTypeBatchAborted,
+				models.EventTypeTurned,
+				models.EventTypeMoved,
+				models.EventTypeEdgeWrapped,
+				models.EventTypeBatchCompleted,
+			},
+		},
+		{
+			name:         "Events after the last sequence",
+			roverId:      1,
+			fromSequence: 14,
+			want:         []models.EventType{},
+		},
+		{
+			name:         "Events of an unknown rover",
+			roverId:      2,
+			fromSequence: 0,
+			want:         []models.EventType{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []models.EventType{}
+			for _, event := range eventStore.Events(tt.roverId, tt.fromSequence) {
+				got = append(got, event.Type)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EventStoreDomain.Events() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventStoreDomain_takeSnapshot(t *testing.T) {
+	eventStore := newEventStoreDomainMocked()
+
+	want := []models.SnapshotDto{
+		{
+			RoverId:  1,
+			Sequence: 3,
+			Batch:    1,
+			Location: models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 3}, Direction: models.DirectionNorth},
+		},
+		{
+			RoverId:  1,
+			Sequence: 6,
+			Batch:    2,
+			Location: models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 3}, Direction: models.DirectionNorth},
+		},
+		{
+			RoverId:  1,
+			Sequence: 9,
+			Batch:    2,
+			Location: models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 5}, Direction: models.DirectionNorth},
+		},
+		{
+			RoverId:  1,
+			Sequence: 12,
+			Batch:    3,
+			Location: models.LocationDto{Point: models.PointDto{XPoint: 0, YPoint: 5}, Direction: models.DirectionWest},
+		},
+	}
+
+	if got := eventStore.snapshots[1]; !reflect.DeepEqual(got, want) {
+		t.Errorf("EventStoreDomain.snapshots = %v, want %v", got, want)
+	}
+}
+
+// newEventStoreDomainMocked returns an event store filled by a rover on a
+// wrapping grid, taking a snapshot every 3 events
+func newEventStoreDomainMocked() *EventStoreDomain {
+	eventStore := NewEventStoreDomain(3).(*EventStoreDomain)
+
+	rover := newRoverDomainMocked()
+	rover.id = 1
+	rover.gridDomain = &GridDomain{models.GridDto{XPointMax: 10, YPointMax: 10, Wrapping: true}}
+	rover.obstacleDomain = &ObstacleDomain{obstacles: []models.ObstacleDto{{Point: models.PointDto{XPoint: 1, YPoint: 6}}}}
+	rover.eventStore = eventStore
+	rover.record(models.EventDto{Type: models.EventTypeLanded, Location: rover.location})
+
+	_, _ = rover.ExecuteCommands([]string{"f", "f", "r"})
+	_, _ = rover.ExecuteCommands([]string{"l", "f", "f", "f", "r"})
+	_, _ = rover.ExecuteCommands([]string{"l", "f", "f"})
+
+	return eventStore
+}
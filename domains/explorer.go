@@ -0,0 +1,105 @@
+package domains
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/mars-rover-go/models"
+)
+
+// ExplorerDomain drives the rover on its own with frontier based exploration:
+// it plans on the known map to the unexplored points, executes the commands
+// and replans with what the rover sensed, including the obstacles aborting
+// a batch
+type ExplorerDomain struct {
+	rover      IRoverDomain
+	grid       models.GridDto
+	gridDomain IGridDomain
+	knownMap   IKnownMapDomain
+	rng        *rand.Rand
+}
+
+// NewExplorerDomain returns an explorer. The seed drives the random strategy.
+func NewExplorerDomain(rover IRoverDomain, grid models.GridDto, knownMap IKnownMapDomain, seed int64) IExplorerDomain {
+	return &ExplorerDomain{
+		rover:      rover,
+		grid:       grid,
+		gridDomain: NewGridDomain(grid),
+		knownMap:   knownMap,
+		rng:        rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (e *ExplorerDomain) Explore(strategy models.ExplorationStrategy, coverageTarget float64, budget int) ([]models.ExplorationStepDto, error) {
+	if strategy != models.ExplorationStrategyNearest && strategy != models.ExplorationStrategyRandom {
+		return nil, fmt.Errorf("exploration strategy '%s' unknown", strategy)
+	}
+
+	e.knownMap.Sync()
+	e.knownMap.Sense(e.rover.Location())
+
+	steps := []models.ExplorationStepDto{}
+	used := 0
+
+	for e.coverage() < coverageTarget && (budget <= 0 || used < budget) {
+		commands, ok := e.plan(strategy)
+		if !ok {
+			break
+		}
+		if budget > 0 && len(commands) > budget-used {
+			commands = commands[:budget-used]
+		}
+
+		location, err := e.rover.ExecuteCommands(commands)
+		used += len(commands)
+
+		e.knownMap.Sync()
+
+		step := models.ExplorationStepDto{
+			Step:           len(steps) + 1,
+			Commands:       commands,
+			Location:       location,
+			CommandsUsed:   used,
+			ExploredPoints: e.knownMap.ExploredPoints(),
+			Coverage:       e.coverage(),
+		}
+		if err != nil {
+			step.Error = err.Error()
+		}
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+// plan returns the commands to the unexplored point chosen by the strategy,
+// moving on the explored points free of known obstacles.
+// Returns false when no unexplored point is reachable.
+func (e *ExplorerDomain) plan(strategy models.ExplorationStrategy) ([]string, bool) {
+	isUnexplored := func(point models.PointDto) bool {
+		return !e.knownMap.IsExplored(point)
+	}
+	canEnter := func(_ models.PointDto, point models.PointDto) bool {
+		return !e.knownMap.IsObstacle(point)
+	}
+
+	search := searchLocations(e.gridDomain, e.rover.Location(), canEnter, isUnexplored)
+	frontier := search.goals(isUnexplored)
+	if len(frontier) == 0 {
+		return nil, false
+	}
+
+	goal := frontier[0]
+	if strategy == models.ExplorationStrategyRandom {
+		goal = frontier[e.rng.Intn(len(frontier))]
+	}
+
+	return search.commandsTo(goal), true
+}
+
+// coverage returns the ratio of the grid points explored
+func (e *ExplorerDomain) coverage() float64 {
+	points := (e.grid.XPointMax + 1) * (e.grid.YPointMax + 1)
+
+	return float64(e.knownMap.ExploredPoints()) / float64(points)
+}
@@ -0,0 +1,115 @@
+package domains
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestExplorerDomain_Explore(t *testing.T) {
+	type args struct {
+		strategy       models.ExplorationStrategy
+		coverageTarget float64
+		budget         int
+	}
+
+	tests := []struct {
+		name             string
+		sensor           models.SensorDto
+		args             args
+		wantCoverage     float64
+		wantCommandsUsed int
+		wantErr          bool
+	}{
+		{
+			name:         "Whole grid explored",
+			sensor:       models.SensorDto{Range: 2},
+			args:         args{strategy: models.ExplorationStrategyNearest, coverageTarget: 1},
+			wantCoverage: 1,
+		},
+		{
+			name:         "Whole grid explored at random",
+			sensor:       models.SensorDto{Range: 2, FieldOfViewDegrees: 90},
+			args:         args{strategy: models.ExplorationStrategyRandom, coverageTarget: 1},
+			wantCoverage: 1,
+		},
+		{
+			name:         "Whole grid explored without sensor range",
+			sensor:       models.SensorDto{},
+			args:         args{strategy: models.ExplorationStrategyNearest, coverageTarget: 1},
+			wantCoverage: 1,
+		},
+		{
+			name:             "Command budget reached",
+			sensor:           models.SensorDto{Range: 1},
+			args:             args{strategy: models.ExplorationStrategyNearest, coverageTarget: 1, budget: 10},
+			wantCommandsUsed: 10,
+		},
+		{
+			name:    "Strategy unknown",
+			sensor:  models.SensorDto{Range: 1},
+			args:    args{strategy: "spiral", coverageTarget: 1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, rover := newExplorerDomainMocked(tt.sensor)
+
+			steps, err := e.Explore(tt.args.strategy, tt.args.coverageTarget, tt.args.budget)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExplorerDomain.Explore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(steps) == 0 {
+				t.Fatalf("ExplorerDomain.Explore() no step")
+			}
+
+			for i := 1; i < len(steps); i++ {
+				if steps[i].Coverage < steps[i-1].Coverage {
+					t.Errorf("ExplorerDomain.Explore() coverage decreased at step %d", steps[i].Step)
+				}
+			}
+
+			last := steps[len(steps)-1]
+			if tt.wantCoverage > 0 && last.Coverage != tt.wantCoverage {
+				t.Errorf("ExplorerDomain.Explore() coverage = %v, want %v", last.Coverage, tt.wantCoverage)
+			}
+			if tt.wantCommandsUsed > 0 && last.CommandsUsed != tt.wantCommandsUsed {
+				t.Errorf("ExplorerDomain.Explore() commands used = %v, want %v", last.CommandsUsed, tt.wantCommandsUsed)
+			}
+			if last.Location != rover.Location() {
+				t.Errorf("ExplorerDomain.Explore() last location = %v, want %v", last.Location, rover.Location())
+			}
+		})
+	}
+}
+
+func TestExplorerDomain_ExploreSeeded(t *testing.T) {
+	explore := func() []models.ExplorationStepDto {
+		e, _ := newExplorerDomainMocked(models.SensorDto{Range: 1})
+		steps, err := e.Explore(models.ExplorationStrategyRandom, 0.8, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return steps
+	}
+
+	if first, second := explore(), explore(); !reflect.DeepEqual(first, second) {
+		t.Errorf("ExplorerDomain.Explore() = %v, then %v with the same seed", first, second)
+	}
+}
+
+func newExplorerDomainMocked(sensor models.SensorDto) (IExplorerDomain, *RoverDomain) {
+	rover := newRoverDomainMocked()
+	eventStore := NewEventStoreDomain(DefaultSnapshotInterval)
+	rover.eventStore = eventStore
+
+	knownMap := NewKnownMapDomain(rover.gridDomain, rover.obstacleDomain, sensor, 0, eventStore)
+	grid := models.GridDto{XPointMax: 10, YPointMax: 10}
+
+	return NewExplorerDomain(&rover, grid, knownMap, 1), &rover
+}
@@ -0,0 +1,139 @@
+package domains
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/mars-rover-go/models"
+)
+
+// maxPlacementAttempts bounds the random draws per obstacle, the boulder
+// fields can be too small for the density
+const maxPlacementAttempts = 100
+
+// MapGeneratorDomain generates maps for the test and training scenarios:
+// obstacles scattered or clustered in boulder fields, a path being cleared
+// between the start and goal when the obstacles separate them
+type MapGeneratorDomain struct{}
+
+func NewMapGeneratorDomain() IMapGeneratorDomain {
+	return &MapGeneratorDomain{}
+}
+
+func (m *MapGeneratorDomain) Generate(settings models.MapGenerationDto) (models.ConfigurationDto, error) {
+	grid := settings.Grid
+	gridDomain := NewGridDomain(grid)
+
+	if grid.XPointMax < 0 || grid.YPointMax < 0 {
+		return models.ConfigurationDto{}, fmt.Errorf("grid size %d %d is negative", grid.XPointMax, grid.YPointMax)
+	}
+	if settings.Density < 0 || settings.Density >= 1 {
+		return models.ConfigurationDto{}, fmt.Errorf("density %v is not between 0 and 1", settings.Density)
+	}
+	if settings.Clusters < 0 || settings.ClusterRadius < 0 {
+		return models.ConfigurationDto{}, fmt.Errorf("clusters %d or cluster radius %d is negative", settings.Clusters, settings.ClusterRadius)
+	}
+	if !gridDomain.IsPointInGrid(settings.Start) || !gridDomain.IsPointInGrid(settings.Goal) {
+		return models.ConfigurationDto{}, fmt.Errorf("start or goal is out the grid")
+	}
+
+	rng := rand.New(rand.NewSource(settings.Seed))
+	points := (grid.XPointMax + 1) * (grid.YPointMax + 1)
+	target := int(math.Round(settings.Density * float64(points)))
+
+	centers := make([]models.PointDto, settings.Clusters)
+	for i := range centers {
+		centers[i] = randomPoint(rng, grid)
+	}
+
+	obstacles := map[models.PointDto]bool{}
+	for attempts := 0; len(obstacles) < target && attempts < target*maxPlacementAttempts; attempts++ {
+		point := randomPoint(rng, grid)
+		if len(centers) > 0 {
+			center := centers[rng.Intn(len(centers))]
+			point = models.PointDto{
+				XPoint: center.XPoint + int(math.Round(rng.NormFloat64()*float64(settings.ClusterRadius))),
+				YPoint: center.YPoint + int(math.Round(rng.NormFloat64()*float64(settings.ClusterRadius))),
+			}
+		}
+
+		if !gridDomain.IsPointInGrid(point) || point == settings.Start || point == settings.Goal {
+			continue
+		}
+		obstacles[point] = true
+	}
+
+	for _, point := range clearedPath(gridDomain, obstacles, settings.Start, settings.Goal) {
+		delete(obstacles, point)
+	}
+
+	config := models.ConfigurationDto{Grid: grid, Obstacle: []models.ObstacleDto{}}
+	for point := range obstacles {
+		config.Obstacle = append(config.Obstacle, models.ObstacleDto{Point: point})
+	}
+	sortObstacles(config.Obstacle)
+
+	return config, nil
+}
+
+// clearedPath returns the obstacles to remove for the goal to be reachable
+// from the start, the fewest possible, searched with a 0-1 breadth first search
+// where entering an obstacle costs one
+func clearedPath(gridDomain IGridDomain, obstacles map[models.PointDto]bool, start models.PointDto, goal models.PointDto) []models.PointDto {
+	costs := map[models.PointDto]int{start: 0}
+	parents := map[models.PointDto]models.PointDto{}
+	// The deque is split in the points at the current cost, popped first,
+	// and the points one more obstacle away
+	current, next := []models.PointDto{start}, []models.PointDto{}
+
+	for len(current) > 0 || len(next) > 0 {
+		if len(current) == 0 {
+			current, next = next, current
+		}
+		point := current[len(current)-1]
+		current = current[:len(current)-1]
+		if point == goal {
+			break
+		}
+
+		for _, direction := range []models.Direction{models.DirectionNorth, models.DirectionEast, models.DirectionSouth, models.DirectionWest} {
+			neighbour, ok := nextLocation(gridDomain, models.LocationDto{Point: point, Direction: direction}, string(models.CommandForward))
+			if !ok {
+				continue
+			}
+
+			cost := costs[point]
+			if obstacles[neighbour.Point] {
+				cost++
+			}
+			if known, seen := costs[neighbour.Point]; seen && known <= cost {
+				continue
+			}
+
+			costs[neighbour.Point] = cost
+			parents[neighbour.Point] = point
+			if cost == costs[point] {
+				current = append(current, neighbour.Point)
+			} else {
+				next = append(next, neighbour.Point)
+			}
+		}
+	}
+
+	cleared := []models.PointDto{}
+	for point := goal; point != start; point = parents[point] {
+		if obstacles[point] {
+			cleared = append(cleared, point)
+		}
+	}
+
+	return cleared
+}
+
+func randomPoint(rng *rand.Rand, grid models.GridDto) models.PointDto {
+	return models.PointDto{
+		XPoint: rng.Intn(grid.XPointMax + 1),
+		YPoint: rng.Intn(grid.YPointMax + 1),
+	}
+}
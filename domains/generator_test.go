@@ -0,0 +1,112 @@
+package domains
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestMapGeneratorDomain_Generate(t *testing.T) {
+	grid := models.GridDto{XPointMax: 19, YPointMax: 19}
+	start := models.PointDto{XPoint: 0, YPoint: 0}
+	goal := models.PointDto{XPoint: 19, YPoint: 19}
+
+	tests := []struct {
+		name          string
+		settings      models.MapGenerationDto
+		wantObstacles int
+		wantErr       bool
+	}{
+		{
+			name:          "Scattered obstacles",
+			settings:      models.MapGenerationDto{Seed: 1, Grid: grid, Density: 0.2, Start: start, Goal: goal},
+			wantObstacles: 80,
+		},
+		{
+			name:     "Boulder fields",
+			settings: models.MapGenerationDto{Seed: 2, Grid: grid, Density: 0.15, Clusters: 3, ClusterRadius: 2, Start: start, Goal: goal},
+		},
+		{
+			name:     "Dense obstacles cleared between start and goal",
+			settings: models.MapGenerationDto{Seed: 3, Grid: grid, Density: 0.7, Start: start, Goal: goal},
+		},
+		{
+			name:          "No obstacles",
+			settings:      models.MapGenerationDto{Seed: 4, Grid: grid, Start: start, Goal: goal},
+			wantObstacles: 0,
+		},
+		{
+			name:     "Density out of range",
+			settings: models.MapGenerationDto{Seed: 1, Grid: grid, Density: 1, Start: start, Goal: goal},
+			wantErr:  true,
+		},
+		{
+			name:     "Clusters negative",
+			settings: models.MapGenerationDto{Seed: 1, Grid: grid, Density: 0.2, Clusters: -1, Start: start, Goal: goal},
+			wantErr:  true,
+		},
+		{
+			name:     "Cluster radius negative",
+			settings: models.MapGenerationDto{Seed: 1, Grid: grid, Density: 0.2, Clusters: 2, ClusterRadius: -2, Start: start, Goal: goal},
+			wantErr:  true,
+		},
+		{
+			name:     "Goal out the grid",
+			settings: models.MapGenerationDto{Seed: 1, Grid: grid, Density: 0.2, Start: start, Goal: models.PointDto{XPoint: 20, YPoint: 0}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMapGeneratorDomain()
+
+			got, err := m.Generate(tt.settings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MapGeneratorDomain.Generate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Grid != tt.settings.Grid {
+				t.Errorf("MapGeneratorDomain.Generate() grid = %v, want %v", got.Grid, tt.settings.Grid)
+			}
+			if tt.wantObstacles > 0 || tt.settings.Density == 0 {
+				if len(got.Obstacle) != tt.wantObstacles {
+					t.Errorf("MapGeneratorDomain.Generate() obstacles = %v, want %v", len(got.Obstacle), tt.wantObstacles)
+				}
+			}
+
+			analysis, err := NewMapAnalysisDomain(got.Grid, NewObstacleDomain(got.Obstacle)).Analyze(tt.settings.Start)
+			if err != nil {
+				t.Fatalf("MapAnalysisDomain.Analyze() error = %v", err)
+			}
+			connected := false
+			for _, point := range analysis.Regions[0].Points {
+				connected = connected || point == tt.settings.Goal
+			}
+			if !connected {
+				t.Errorf("MapGeneratorDomain.Generate() goal %v not reachable from %v", tt.settings.Goal, tt.settings.Start)
+			}
+
+			again, _ := m.Generate(tt.settings)
+			if !reflect.DeepEqual(got, again) {
+				t.Errorf("MapGeneratorDomain.Generate() = %v, then %v with the same seed", got, again)
+			}
+		})
+	}
+}
+
+func TestMapGeneratorDomain_GenerateSeeds(t *testing.T) {
+	settings := models.MapGenerationDto{Grid: models.GridDto{XPointMax: 9, YPointMax: 9}, Density: 0.3, Goal: models.PointDto{XPoint: 9, YPoint: 9}}
+	m := NewMapGeneratorDomain()
+
+	settings.Seed = 1
+	first, _ := m.Generate(settings)
+	settings.Seed = 2
+	second, _ := m.Generate(settings)
+
+	if reflect.DeepEqual(first.Obstacle, second.Obstacle) {
+		t.Errorf("MapGeneratorDomain.Generate() same obstacles with seeds 1 and 2")
+	}
+}
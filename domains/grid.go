@@ -18,3 +18,23 @@ func (g *GridDomain) IsPointInGrid(point models.PointDto) bool {
 
 	return true
 }
+
+func (g *GridDomain) WrapPoint(point models.PointDto) (models.PointDto, bool) {
+	if !g.grid.Wrapping {
+		return point, false
+	}
+
+	return models.PointDto{
+		XPoint: wrapCoordinate(point.XPoint, g.grid.XPointMax),
+		YPoint: wrapCoordinate(point.YPoint, g.grid.YPointMax),
+	}, true
+}
+
+func (g *GridDomain) HasDiagonals() bool {
+	return g.grid.Diagonals
+}
+
+func wrapCoordinate(value int, max int) int {
+	size := max + 1
+	return ((value % size) + size) % size
+}
@@ -73,3 +73,57 @@ func TestGridDomain_IsPointInGrid(t *testing.T) {
 		})
 	}
 }
+
+func TestGridDomain_WrapPoint(t *testing.T) {
+	type args struct {
+		point models.PointDto
+	}
+
+	wrappingGridDomain := &GridDomain{
+		grid: models.GridDto{XPointMax: 10, YPointMax: 10, Wrapping: true},
+	}
+
+	tests := []struct {
+		name        string
+		g           *GridDomain
+		args        args
+		want        models.PointDto
+		wantWrapped bool
+	}{
+		{
+			name: "No wrapping",
+			g:    &GridDomain{grid: models.GridDto{XPointMax: 10, YPointMax: 10}},
+			args: args{
+				point: models.PointDto{XPoint: 11, YPoint: 6},
+			},
+			want:        models.PointDto{XPoint: 11, YPoint: 6},
+			wantWrapped: false,
+		},
+		{
+			name: "Wrap XPoint higher than XPointMax",
+			g:    wrappingGridDomain,
+			args: args{
+				point: models.PointDto{XPoint: 11, YPoint: 6},
+			},
+			want:        models.PointDto{XPoint: 0, YPoint: 6},
+			wantWrapped: true,
+		},
+		{
+			name: "Wrap YPoint minor to 0",
+			g:    wrappingGridDomain,
+			args: args{
+				point: models.PointDto{XPoint: 3, YPoint: -1},
+			},
+			want:        models.PointDto{XPoint: 3, YPoint: 10},
+			wantWrapped: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotWrapped := tt.g.WrapPoint(tt.args.point)
+			if got != tt.want || gotWrapped != tt.wantWrapped {
+				t.Errorf("GridDomain.WrapPoint() = %v, %v, want %v, %v", got, gotWrapped, tt.want, tt.wantWrapped)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package domains
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/mars-rover-go/models"
+)
+
+// KnownMapDomain is the map the rover builds with its sensor, the ground
+// truth obstacles are only known once sensed or encountered
+type KnownMapDomain struct {
+	gridDomain  IGridDomain
+	groundTruth IObstacleDomain
+	sensor      models.SensorDto
+	roverId     int
+	eventStore  IEventStoreDomain
+
+	mu sync.RWMutex
+	// explored holds the points sensed so far, true for the obstacles
+	explored map[models.PointDto]bool
+
+	// syncMu serializes the syncs, lastSequence being the last event observed
+	syncMu       sync.Mutex
+	lastSequence int
+}
+
+// NewKnownMapDomain returns the known map of the rover, synced from the
+// rover events of the event store. Without event store the map is only
+// updated by Observe and Sense.
+func NewKnownMapDomain(gridDomain IGridDomain, groundTruth IObstacleDomain, sensor models.SensorDto, roverId int, eventStore IEventStoreDomain) IKnownMapDomain {
+	return &KnownMapDomain{
+		gridDomain:  gridDomain,
+		groundTruth: groundTruth,
+		sensor:      sensor,
+		roverId:     roverId,
+		eventStore:  eventStore,
+		explored:    map[models.PointDto]bool{},
+	}
+}
+
+func (k *KnownMapDomain) Sync() {
+	if k.eventStore == nil {
+		return
+	}
+
+	k.syncMu.Lock()
+	defer k.syncMu.Unlock()
+
+	events := k.eventStore.Events(k.roverId, k.lastSequence)
+	k.Observe(events...)
+	if len(events) > 0 {
+		k.lastSequence = events[len(events)-1].Sequence
+	}
+}
+
+func (k *KnownMapDomain) IsObstacle(point models.PointDto) bool {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.explored[point]
+}
+
+func (k *KnownMapDomain) IsExplored(point models.PointDto) bool {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	_, ok := k.explored[point]
+	return ok
+}
+
+func (k *KnownMapDomain) Observe(events ...models.EventDto) {
+	for _, event := range events {
+		switch event.Type {
+		case models.EventTypeLanded, models.EventTypeMoved, models.EventTypeTurned, models.EventTypeEdgeWrapped:
+			k.Sense(event.Location)
+		case models.EventTypeObstacleEncountered:
+			k.mu.Lock()
+			k.explored[event.Point] = true
+			k.mu.Unlock()
+		}
+	}
+}
+
+func (k *KnownMapDomain) Sense(location models.LocationDto) {
+	sensed := map[models.PointDto]bool{location.Point: k.groundTruth.IsObstacle(location.Point)}
+
+	for dx := -k.sensor.Range; dx <= k.sensor.Range; dx++ {
+		for dy := -k.sensor.Range; dy <= k.sensor.Range; dy++ {
+			if !k.inSensorField(location.Direction, dx, dy) {
+				continue
+			}
+
+			point, ok := k.pointAt(location.Point, dx, dy)
+			if !ok || !k.inLineOfSight(location.Point, dx, dy) {
+				continue
+			}
+			sensed[point] = k.groundTruth.IsObstacle(point)
+		}
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for point, isObstacle := range sensed {
+		k.explored[point] = isObstacle
+	}
+}
+
+func (k *KnownMapDomain) KnownObstacles() []models.ObstacleDto {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	obstacles := []models.ObstacleDto{}
+	for point, isObstacle := range k.explored {
+		if isObstacle {
+			obstacles = append(obstacles, models.ObstacleDto{Point: point})
+		}
+	}
+	sortObstacles(obstacles)
+
+	return obstacles
+}
+
+func (k *KnownMapDomain) ExploredPoints() int {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return len(k.explored)
+}
+
+// inSensorField checks if the offset is in the sensor range and in its field
+// of view, centered on the direction
+func (k *KnownMapDomain) inSensorField(direction models.Direction, dx int, dy int) bool {
+	if dx == 0 && dy == 0 {
+		return true
+	}
+	if dx*dx+dy*dy > k.sensor.Range*k.sensor.Range {
+		return false
+	}
+	if k.sensor.FieldOfViewDegrees <= 0 || k.sensor.FieldOfViewDegrees >= 360 {
+		return true
+	}
+
+	forward := nextPoint(models.LocationDto{Direction: direction}, models.MoveTypeForward)
+	angle := math.Atan2(float64(dy), float64(dx)) - math.Atan2(float64(forward.YPoint), float64(forward.XPoint))
+	angle = math.Abs(math.Remainder(angle, 2*math.Pi))
+
+	return angle <= float64(k.sensor.FieldOfViewDegrees)/2*math.Pi/180+1e-9
+}
+
+// inLineOfSight checks no obstacle stands between the origin and the offset
+func (k *KnownMapDomain) inLineOfSight(origin models.PointDto, dx int, dy int) bool {
+	steps := absInt(dx)
+	if absInt(dy) > steps {
+		steps = absInt(dy)
+	}
+
+	for i := 1; i < steps; i++ {
+		x := int(math.Round(float64(dx*i) / float64(steps)))
+		y := int(math.Round(float64(dy*i) / float64(steps)))
+
+		point, ok := k.pointAt(origin, x, y)
+		if !ok || k.groundTruth.IsObstacle(point) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// pointAt returns the point at the offset, wrapped on the grid edges if the
+// grid wraps. Returns false when the point is out the grid.
+func (k *KnownMapDomain) pointAt(origin models.PointDto, dx int, dy int) (models.PointDto, bool) {
+	point := models.PointDto{XPoint: origin.XPoint + dx, YPoint: origin.YPoint + dy}
+	if k.gridDomain.IsPointInGrid(point) {
+		return point, true
+	}
+
+	return k.gridDomain.WrapPoint(point)
+}
+
+func absInt(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
+
+// sortObstacles orders the obstacles by point, x first
+func sortObstacles(obstacles []models.ObstacleDto) {
+	sort.Slice(obstacles, func(i, j int) bool {
+		if obstacles[i].Point.XPoint != obstacles[j].Point.XPoint {
+			return obstacles[i].Point.XPoint < obstacles[j].Point.XPoint
+		}
+
+		return obstacles[i].Point.YPoint < obstacles[j].Point.YPoint
+	})
+}
+
+// sortPoints orders the points, x first
+func sortPoints(points []models.PointDto) {
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].XPoint != points[j].XPoint {
+			return points[i].XPoint < points[j].XPoint
+		}
+
+		return points[i].YPoint < points[j].YPoint
+	})
+}
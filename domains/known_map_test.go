@@ -0,0 +1,120 @@
+package domains
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestKnownMapDomain_Sense(t *testing.T) {
+	type args struct {
+		location models.LocationDto
+		point    models.PointDto
+	}
+
+	obstacles := []models.ObstacleDto{{Point: models.PointDto{XPoint: 5, YPoint: 6}}}
+	north := models.LocationDto{Point: models.PointDto{XPoint: 5, YPoint: 5}, Direction: models.DirectionNorth}
+	east := models.LocationDto{Point: models.PointDto{XPoint: 5, YPoint: 5}, Direction: models.DirectionEast}
+
+	tests := []struct {
+		name         string
+		grid         models.GridDto
+		sensor       models.SensorDto
+		args         args
+		wantExplored bool
+		wantObstacle bool
+	}{
+		{
+			name:         "Rover point explored",
+			grid:         models.GridDto{XPointMax: 10, YPointMax: 10},
+			sensor:       models.SensorDto{},
+			args:         args{location: north, point: north.Point},
+			wantExplored: true,
+		},
+		{
+			name:         "Obstacle in range",
+			grid:         models.GridDto{XPointMax: 10, YPointMax: 10},
+			sensor:       models.SensorDto{Range: 2},
+			args:         args{location: north, point: models.PointDto{XPoint: 5, YPoint: 6}},
+			wantExplored: true,
+			wantObstacle: true,
+		},
+		{
+			name:         "Point out of range",
+			grid:         models.GridDto{XPointMax: 10, YPointMax: 10},
+			sensor:       models.SensorDto{Range: 2},
+			args:         args{location: north, point: models.PointDto{XPoint: 7, YPoint: 7}},
+			wantExplored: false,
+		},
+		{
+			name:         "Point hidden behind an obstacle",
+			grid:         models.GridDto{XPointMax: 10, YPointMax: 10},
+			sensor:       models.SensorDto{Range: 2},
+			args:         args{location: north, point: models.PointDto{XPoint: 5, YPoint: 7}},
+			wantExplored: false,
+		},
+		{
+			name:         "Point behind the rover out of the field of view",
+			grid:         models.GridDto{XPointMax: 10, YPointMax: 10},
+			sensor:       models.SensorDto{Range: 2, FieldOfViewDegrees: 90},
+			args:         args{location: north, point: models.PointDto{XPoint: 5, YPoint: 4}},
+			wantExplored: false,
+		},
+		{
+			name:         "Point on the edge of the field of view",
+			grid:         models.GridDto{XPointMax: 10, YPointMax: 10},
+			sensor:       models.SensorDto{Range: 2, FieldOfViewDegrees: 90},
+			args:         args{location: east, point: models.PointDto{XPoint: 6, YPoint: 4}},
+			wantExplored: true,
+		},
+		{
+			name:         "Point across the wrapped edge",
+			grid:         models.GridDto{XPointMax: 6, YPointMax: 10, Wrapping: true},
+			sensor:       models.SensorDto{Range: 2, FieldOfViewDegrees: 90},
+			args:         args{location: east, point: models.PointDto{XPoint: 0, YPoint: 5}},
+			wantExplored: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := NewKnownMapDomain(NewGridDomain(tt.grid), NewObstacleDomain(obstacles), tt.sensor, 0, nil)
+			k.Sense(tt.args.location)
+
+			if got := k.IsExplored(tt.args.point); got != tt.wantExplored {
+				t.Errorf("KnownMapDomain.IsExplored() = %v, want %v", got, tt.wantExplored)
+			}
+			if got := k.IsObstacle(tt.args.point); got != tt.wantObstacle {
+				t.Errorf("KnownMapDomain.IsObstacle() = %v, want %v", got, tt.wantObstacle)
+			}
+		})
+	}
+}
+
+func TestKnownMapDomain_Sync(t *testing.T) {
+	eventStore := NewEventStoreDomain(DefaultSnapshotInterval)
+	rover := newRoverDomainMocked()
+	rover.eventStore = eventStore
+
+	k := NewKnownMapDomain(rover.gridDomain, rover.obstacleDomain, models.SensorDto{}, 0, eventStore)
+
+	// Without range the obstacle at (2,6) is only known once the rover bumps into it
+	_, err := rover.ExecuteCommands([]string{"r", "f", "l", "f", "f", "f", "f", "f"})
+	if err == nil {
+		t.Fatalf("RoverDomain.ExecuteCommands() error = nil, want obstacle detected")
+	}
+	k.Sync()
+	// The events already observed are not observed again
+	k.Sync()
+
+	want := []models.ObstacleDto{{Point: models.PointDto{XPoint: 2, YPoint: 6}}}
+	if got := k.KnownObstacles(); !reflect.DeepEqual(got, want) {
+		t.Errorf("KnownMapDomain.KnownObstacles() = %v, want %v", got, want)
+	}
+	if k.IsObstacle(models.PointDto{XPoint: 8, YPoint: 5}) {
+		t.Errorf("KnownMapDomain.IsObstacle() = true for the obstacle never sensed")
+	}
+	if got := k.ExploredPoints(); got != 7 {
+		t.Errorf("KnownMapDomain.ExploredPoints() = %d, want the 6 points along the path and the obstacle", got)
+	}
+}
@@ -0,0 +1,132 @@
+package domains
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mars-rover-go/models"
+)
+
+// frameHeaderSize is the size of the sequence, type and payload length
+const frameHeaderSize = 9
+
+// LinkDomain simulates a radio link delaying the frames by the light time
+// and dropping, duplicating, reordering or corrupting them with the
+// configured probabilities
+type LinkDomain struct {
+	clock     IClockDomain
+	lightTime time.Duration
+	link      models.LinkDto
+
+	mu     sync.Mutex
+	rng    *rand.Rand
+	held   []func()
+	closed bool
+}
+
+func NewLinkDomain(clock IClockDomain, lightTime time.Duration, link models.LinkDto) ILinkDomain {
+	return &LinkDomain{
+		clock:     clock,
+		lightTime: lightTime,
+		link:      link,
+		rng:       rand.New(rand.NewSource(link.Seed)),
+	}
+}
+
+func (l *LinkDomain) Transmit(frame []byte, deliver func(frame []byte)) {
+	l.mu.Lock()
+
+	if l.rng.Float64() < l.link.DropProbability {
+		l.mu.Unlock()
+		return
+	}
+
+	transmitted := frame
+	if l.rng.Float64() < l.link.CorruptProbability && len(frame) > 0 {
+		transmitted = append([]byte{}, frame...)
+		bit := l.rng.Intn(len(transmitted) * 8)
+		transmitted[bit/8] ^= 1 << uint(bit%8)
+	}
+
+	deliveries := []func(){func() { deliver(transmitted) }}
+	if l.rng.Float64() < l.link.DuplicateProbability {
+		deliveries = append(deliveries, deliveries[0])
+	}
+
+	// A reordered frame is held back and sent after the next one
+	if l.held == nil && l.rng.Float64() < l.link.ReorderProbability {
+		l.held = deliveries
+		l.mu.Unlock()
+		return
+	}
+
+	deliveries = append(deliveries, l.held...)
+	l.held = nil
+	l.mu.Unlock()
+
+	// The clock calls the deliveries due at the same time in order
+	for _, d := range deliveries {
+		d := d
+		l.clock.AfterFunc(l.lightTime, func() {
+			if !l.isClosed() {
+				d()
+			}
+		})
+	}
+}
+
+func (l *LinkDomain) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+}
+
+func (l *LinkDomain) isClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.closed
+}
+
+// EncodeFrame serializes the frame followed by its CRC-32
+func EncodeFrame(frame models.FrameDto) []byte {
+	data := make([]byte, frameHeaderSize, frameHeaderSize+len(frame.Payload)+crc32.Size)
+	binary.BigEndian.PutUint32(data[0:4], frame.Sequence)
+	data[4] = byte(frame.Type)
+	binary.BigEndian.PutUint32(data[5:9], uint32(len(frame.Payload)))
+	data = append(data, frame.Payload...)
+
+	checksum := make([]byte, crc32.Size)
+	binary.BigEndian.PutUint32(checksum, crc32.ChecksumIEEE(data))
+
+	return append(data, checksum...)
+}
+
+// DecodeFrame deserializes a frame, rejecting it when the CRC-32 does not match
+func DecodeFrame(data []byte) (models.FrameDto, error) {
+	if len(data) < frameHeaderSize+crc32.Size {
+		return models.FrameDto{}, fmt.Errorf("frame too short")
+	}
+
+	body := data[:len(data)-crc32.Size]
+	checksum := binary.BigEndian.Uint32(data[len(data)-crc32.Size:])
+	if crc32.ChecksumIEEE(body) != checksum {
+		return models.FrameDto{}, fmt.Errorf("frame checksum mismatch")
+	}
+
+	length := binary.BigEndian.Uint32(body[5:9])
+	if int(length) != len(body)-frameHeaderSize {
+		return models.FrameDto{}, fmt.Errorf("frame length mismatch")
+	}
+
+	return models.FrameDto{
+		Sequence: binary.BigEndian.Uint32(body[0:4]),
+		Type:     models.FrameType(body[4]),
+		Payload:  body[frameHeaderSize:],
+	}, nil
+}
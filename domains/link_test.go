@@ -0,0 +1,119 @@
+package domains
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestDecodeFrame(t *testing.T) {
+	type args struct {
+		data []byte
+	}
+
+	frame := models.FrameDto{Sequence: 42, Type: models.FrameTypeCommands, Payload: []byte(`["f","r"]`)}
+	encoded := EncodeFrame(frame)
+
+	corrupted := append([]byte{}, encoded...)
+	corrupted[10] ^= 0x01
+
+	tests := []struct {
+		name    string
+		args    args
+		want    models.FrameDto
+		wantErr bool
+	}{
+		{
+			name: "Frame ok",
+			args: args{
+				data: encoded,
+			},
+			want:    frame,
+			wantErr: false,
+		},
+		{
+			name: "Frame corrupted",
+			args: args{
+				data: corrupted,
+			},
+			want:    models.FrameDto{},
+			wantErr: true,
+		},
+		{
+			name: "Frame too short",
+			args: args{
+				data: encoded[:5],
+			},
+			want:    models.FrameDto{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeFrame(tt.args.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeFrame() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeFrame() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkDomain_Transmit(t *testing.T) {
+	tests := []struct {
+		name string
+		link models.LinkDto
+		want []byte
+	}{
+		{
+			name: "Perfect link",
+			link: models.LinkDto{},
+			want: []byte{1, 2, 3, 4},
+		},
+		{
+			name: "Frames dropped",
+			link: models.LinkDto{DropProbability: 1},
+			want: []byte{},
+		},
+		{
+			name: "Frames duplicated",
+			link: models.LinkDto{DuplicateProbability: 1},
+			want: []byte{1, 1, 2, 2, 3, 3, 4, 4},
+		},
+		{
+			name: "Frames reordered",
+			link: models.LinkDto{ReorderProbability: 1},
+			want: []byte{2, 1, 4, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewSimulationClockDomain(time.Millisecond)
+			l := NewLinkDomain(clock, 10*time.Millisecond, tt.link)
+
+			delivered := make(chan byte, 16)
+			for i := byte(1); i <= 4; i++ {
+				l.Transmit([]byte{i}, func(frame []byte) {
+					delivered <- frame[0]
+				})
+			}
+
+			clock.Run()
+			l.Close()
+			close(delivered)
+
+			got := []byte{}
+			for frame := range delivered {
+				got = append(got, frame)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LinkDomain.Transmit() delivered = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
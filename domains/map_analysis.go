@@ -0,0 +1,279 @@
+package domains
+
+import (
+	"fmt"
+
+	"github.com/mars-rover-go/models"
+)
+
+// MapAnalysisDomain analyzes the connectivity of the grid and obstacles: the
+// rover turns in place, so the points it reaches are the ones connected to
+// its start point by forward moves. The moves are searched on the rover
+// obstacles, where a slope can be driven down but not climbed, so the
+// connections go one way.
+type MapAnalysisDomain struct {
+	grid           models.GridDto
+	gridDomain     IGridDomain
+	obstacleDomain IObstacleDomain
+}
+
+func NewMapAnalysisDomain(grid models.GridDto, obstacleDomain IObstacleDomain) IMapAnalysisDomain {
+	return &MapAnalysisDomain{
+		grid:           grid,
+		gridDomain:     NewGridDomain(grid),
+		obstacleDomain: obstacleDomain,
+	}
+}
+
+// mapGraph are the moves between the free points, and the same moves
+// reversed
+type mapGraph struct {
+	next     map[models.PointDto][]models.PointDto
+	previous map[models.PointDto][]models.PointDto
+}
+
+func (m *MapAnalysisDomain) Analyze(start models.PointDto) (models.MapAnalysisDto, error) {
+	if !m.gridDomain.IsPointInGrid(start) {
+		return models.MapAnalysisDto{}, fmt.Errorf("start point %d %d is out the grid", start.XPoint, start.YPoint)
+	}
+	if m.obstacleDomain.IsObstacle(start) {
+		return models.MapAnalysisDto{}, fmt.Errorf("start point %d %d is an obstacle", start.XPoint, start.YPoint)
+	}
+
+	// The region of the start point comes first, then the others in the
+	// grid order
+	points := []models.PointDto{start}
+	for x := 0; x <= m.grid.XPointMax; x++ {
+		for y := 0; y <= m.grid.YPointMax; y++ {
+			if point := (models.PointDto{XPoint: x, YPoint: y}); point != start && !m.obstacleDomain.IsObstacle(point) {
+				points = append(points, point)
+			}
+		}
+	}
+
+	graph := m.graph(points)
+	reachable := reachableFrom(start, graph.next, func(models.PointDto) bool { return true })
+	components := strongComponents(points, graph)
+
+	analysis := models.MapAnalysisDto{Start: start, FreePoints: len(points), ReachablePoints: len(reachable), Regions: []models.MapRegionDto{}}
+	regionOf := map[int]int{}
+	for _, point := range points {
+		if _, ok := regionOf[components[point]]; ok {
+			continue
+		}
+		regionOf[components[point]] = len(analysis.Regions)
+		analysis.Regions = append(analysis.Regions, models.MapRegionDto{Id: len(analysis.Regions) + 1, Reachable: reachable[point]})
+	}
+	for _, point := range points {
+		region := &analysis.Regions[regionOf[components[point]]]
+		region.Points = append(region.Points, point)
+	}
+
+	for i := range analysis.Regions {
+		region := &analysis.Regions[i]
+		sortPoints(region.Points)
+		region.ChokePoints = chokePoints(region.Points, graph)
+	}
+
+	return analysis, nil
+}
+
+// graph returns the forward moves between the points
+func (m *MapAnalysisDomain) graph(points []models.PointDto) mapGraph {
+	graph := mapGraph{next: map[models.PointDto][]models.PointDto{}, previous: map[models.PointDto][]models.PointDto{}}
+	canEnter := freeOf(m.obstacleDomain)
+
+	for _, point := range points {
+		seen := map[models.PointDto]bool{point: true}
+		for _, direction := range headings(m.gridDomain) {
+			location := models.LocationDto{Point: point, Direction: direction}
+			next, ok := nextLocation(m.gridDomain, location, string(models.CommandForward))
+			if !ok || seen[next.Point] || !canStep(m.gridDomain, location, next, string(models.CommandForward), canEnter) {
+				continue
+			}
+			seen[next.Point] = true
+			graph.next[point] = append(graph.next[point], next.Point)
+			graph.previous[next.Point] = append(graph.previous[next.Point], point)
+		}
+	}
+
+	return graph
+}
+
+// reachableFrom returns the points reached from the origin by the moves,
+// only through the points kept
+func reachableFrom(origin models.PointDto, moves map[models.PointDto][]models.PointDto, keep func(models.PointDto) bool) map[models.PointDto]bool {
+	reached := map[models.PointDto]bool{origin: true}
+	queue := []models.PointDto{origin}
+	for len(queue) > 0 {
+		point := queue[0]
+		queue = queue[1:]
+		for _, next := range moves[point] {
+			if !reached[next] && keep(next) {
+				reached[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return reached
+}
+
+// strongComponents returns the component of each point, the points of a
+// component reaching each other, found with the depth first search of Tarjan
+func strongComponents(points []models.PointDto, graph mapGraph) map[models.PointDto]int {
+	order := map[models.PointDto]int{}
+	low := map[models.PointDto]int{}
+	stacked := map[models.PointDto]bool{}
+	stack := []models.PointDto{}
+	components := map[models.PointDto]int{}
+
+	var visit func(point models.PointDto)
+	visit = func(point models.PointDto) {
+		order[point] = len(order) + 1
+		low[point] = order[point]
+		stack = append(stack, point)
+		stacked[point] = true
+
+		for _, next := range graph.next[point] {
+			if _, visited := order[next]; !visited {
+				visit(next)
+				if low[next] < low[point] {
+					low[point] = low[next]
+				}
+			} else if stacked[next] && order[next] < low[point] {
+				low[point] = order[next]
+			}
+		}
+
+		if low[point] == order[point] {
+			component := len(components) + 1
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				stacked[top] = false
+				components[top] = component
+				if top == point {
+					break
+				}
+			}
+		}
+	}
+
+	for _, point := range points {
+		if _, visited := order[point]; !visited {
+			visit(point)
+		}
+	}
+
+	return components
+}
+
+// chokePoints returns the points of the region whose blocking leaves points
+// unable to reach each other. They are the points other than the first one
+// dominating a point, all the paths from the first point to it or back going
+// through them, and the first point when the others do not reach each other
+// without it.
+func chokePoints(region []models.PointDto, graph mapGraph) []models.PointDto {
+	isChoke := map[models.PointDto]bool{}
+	root := region[0]
+	inRegion := map[models.PointDto]bool{}
+	for _, point := range region {
+		inRegion[point] = true
+	}
+
+	for _, moves := range []map[models.PointDto][]models.PointDto{graph.next, graph.previous} {
+		for point, dominator := range immediateDominators(root, moves, inRegion) {
+			if point != root && dominator != root {
+				isChoke[dominator] = true
+			}
+		}
+	}
+
+	if len(region) > 2 {
+		other := region[1]
+		withoutRoot := func(point models.PointDto) bool { return inRegion[point] && point != root }
+		if len(reachableFrom(other, graph.next, withoutRoot)) < len(region)-1 ||
+			len(reachableFrom(other, graph.previous, withoutRoot)) < len(region)-1 {
+			isChoke[root] = true
+		}
+	}
+
+	chokePoints := []models.PointDto{}
+	for _, point := range region {
+		if isChoke[point] {
+			chokePoints = append(chokePoints, point)
+		}
+	}
+
+	return chokePoints
+}
+
+// immediateDominators returns the immediate dominator of each point reached
+// from the root, with the iterative algorithm of Cooper, Harvey and Kennedy
+func immediateDominators(root models.PointDto, moves map[models.PointDto][]models.PointDto, inRegion map[models.PointDto]bool) map[models.PointDto]models.PointDto {
+	// The points in depth first post order
+	postOrder := map[models.PointDto]int{}
+	points := []models.PointDto{}
+	var visit func(point models.PointDto)
+	visit = func(point models.PointDto) {
+		postOrder[point] = -1
+		for _, next := range moves[point] {
+			if _, visited := postOrder[next]; !visited && inRegion[next] {
+				visit(next)
+			}
+		}
+		postOrder[point] = len(points)
+		points = append(points, point)
+	}
+	visit(root)
+
+	previous := map[models.PointDto][]models.PointDto{}
+	for _, point := range points {
+		for _, next := range moves[point] {
+			if inRegion[next] {
+				previous[next] = append(previous[next], point)
+			}
+		}
+	}
+
+	intersect := func(a models.PointDto, b models.PointDto, dominators map[models.PointDto]models.PointDto) models.PointDto {
+		for a != b {
+			for postOrder[a] < postOrder[b] {
+				a = dominators[a]
+			}
+			for postOrder[b] < postOrder[a] {
+				b = dominators[b]
+			}
+		}
+		return a
+	}
+
+	dominators := map[models.PointDto]models.PointDto{root: root}
+	for changed := true; changed; {
+		changed = false
+		// Reverse post order, the root first
+		for i := len(points) - 2; i >= 0; i-- {
+			point := points[i]
+			var dominator *models.PointDto
+			for _, p := range previous[point] {
+				if _, ok := dominators[p]; !ok {
+					continue
+				}
+				if dominator == nil {
+					p := p
+					dominator = &p
+				} else {
+					d := intersect(p, *dominator, dominators)
+					dominator = &d
+				}
+			}
+			if current, ok := dominators[point]; dominator != nil && (!ok || current != *dominator) {
+				dominators[point] = *dominator
+				changed = true
+			}
+		}
+	}
+
+	return dominators
+}
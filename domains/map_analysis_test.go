@@ -0,0 +1,163 @@
+package domains
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestMapAnalysisDomain_Analyze(t *testing.T) {
+	// A wall splitting the grid in two, open at 2 2
+	wall := []models.ObstacleDto{
+		{Point: models.PointDto{XPoint: 2, YPoint: 0}},
+		{Point: models.PointDto{XPoint: 2, YPoint: 1}},
+		{Point: models.PointDto{XPoint: 2, YPoint: 3}},
+		{Point: models.PointDto{XPoint: 2, YPoint: 4}},
+	}
+
+	type want struct {
+		freePoints      int
+		reachablePoints int
+		regions         int
+		chokePoints     []models.PointDto
+		enclosed        [][]models.PointDto
+	}
+
+	tests := []struct {
+		name   string
+		config models.ConfigurationDto
+		// elevations adds a terrain with a maximum slope of 1
+		elevations [][]float64
+		start      models.PointDto
+		want       want
+		wantErr    bool
+	}{
+		{
+			name:   "Grid free of obstacles",
+			config: models.ConfigurationDto{Grid: models.GridDto{XPointMax: 4, YPointMax: 4}},
+			start:  models.PointDto{XPoint: 0, YPoint: 0},
+			want:   want{freePoints: 25, reachablePoints: 25, regions: 1, chokePoints: []models.PointDto{}},
+		},
+		{
+			name:   "Wall with an opening",
+			config: models.ConfigurationDto{Grid: models.GridDto{XPointMax: 4, YPointMax: 4}, Obstacle: wall},
+			start:  models.PointDto{XPoint: 0, YPoint: 0},
+			want: want{
+				freePoints:      21,
+				reachablePoints: 21,
+				regions:         1,
+				chokePoints:     []models.PointDto{{XPoint: 1, YPoint: 2}, {XPoint: 2, YPoint: 2}, {XPoint: 3, YPoint: 2}},
+			},
+		},
+		{
+			name:   "Wall with an opening on a wrapping grid",
+			config: models.ConfigurationDto{Grid: models.GridDto{XPointMax: 4, YPointMax: 4, Wrapping: true}, Obstacle: wall},
+			start:  models.PointDto{XPoint: 0, YPoint: 0},
+			want:   want{freePoints: 21, reachablePoints: 21, regions: 1, chokePoints: []models.PointDto{}},
+		},
+		{
+			name: "Corner enclosed by obstacles",
+			config: models.ConfigurationDto{
+				Grid: models.GridDto{XPointMax: 4, YPointMax: 4},
+				Obstacle: []models.ObstacleDto{
+					{Point: models.PointDto{XPoint: 3, YPoint: 4}},
+					{Point: models.PointDto{XPoint: 4, YPoint: 3}},
+				},
+			},
+			start: models.PointDto{XPoint: 0, YPoint: 0},
+			want: want{
+				freePoints:      23,
+				reachablePoints: 22,
+				regions:         2,
+				chokePoints:     []models.PointDto{},
+				enclosed:        [][]models.PointDto{{{XPoint: 4, YPoint: 4}}},
+			},
+		},
+		{
+			name:       "Pit driven into",
+			config:     models.ConfigurationDto{Grid: models.GridDto{XPointMax: 2, YPointMax: 0}},
+			elevations: [][]float64{{0, 0, -10}},
+			start:      models.PointDto{XPoint: 0, YPoint: 0},
+			want:       want{freePoints: 3, reachablePoints: 3, regions: 2, chokePoints: []models.PointDto{}},
+		},
+		{
+			name:       "Start in a pit",
+			config:     models.ConfigurationDto{Grid: models.GridDto{XPointMax: 2, YPointMax: 0}},
+			elevations: [][]float64{{-10, 0, 0}},
+			start:      models.PointDto{XPoint: 0, YPoint: 0},
+			want: want{
+				freePoints:      3,
+				reachablePoints: 1,
+				regions:         2,
+				chokePoints:     []models.PointDto{},
+				enclosed:        [][]models.PointDto{{{XPoint: 1, YPoint: 0}, {XPoint: 2, YPoint: 0}}},
+			},
+		},
+		{
+			name:       "Loop with a drop driven one way",
+			config:     models.ConfigurationDto{Grid: models.GridDto{XPointMax: 1, YPointMax: 1}},
+			elevations: [][]float64{{0, -2}, {0, -1}},
+			start:      models.PointDto{XPoint: 0, YPoint: 0},
+			want: want{
+				freePoints:      4,
+				reachablePoints: 4,
+				regions:         1,
+				chokePoints:     []models.PointDto{{XPoint: 0, YPoint: 1}, {XPoint: 1, YPoint: 1}},
+			},
+		},
+		{
+			name:    "Start point on an obstacle",
+			config:  models.ConfigurationDto{Grid: models.GridDto{XPointMax: 4, YPointMax: 4}, Obstacle: wall},
+			start:   models.PointDto{XPoint: 2, YPoint: 0},
+			wantErr: true,
+		},
+		{
+			name:    "Start point out the grid",
+			config:  models.ConfigurationDto{Grid: models.GridDto{XPointMax: 4, YPointMax: 4}},
+			start:   models.PointDto{XPoint: 5, YPoint: 0},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obstacleDomain IObstacleDomain = NewObstacleDomain(tt.config.Obstacle)
+			if tt.elevations != nil {
+				terrain, err := NewTerrainDomain(tt.config.Grid, models.HeightmapDto{Elevations: tt.elevations}, models.TerrainDto{CellSizeMeters: 1, MaxSlope: 1})
+				if err != nil {
+					t.Fatal(err)
+				}
+				obstacleDomain = NewCompositeObstacleDomain(obstacleDomain, terrain)
+			}
+			m := NewMapAnalysisDomain(tt.config.Grid, obstacleDomain)
+
+			got, err := m.Analyze(tt.start)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MapAnalysisDomain.Analyze() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.FreePoints != tt.want.freePoints {
+				t.Errorf("MapAnalysisDomain.Analyze() free points = %v, want %v", got.FreePoints, tt.want.freePoints)
+			}
+			if got.ReachablePoints != tt.want.reachablePoints {
+				t.Errorf("MapAnalysisDomain.Analyze() reachable points = %v, want %v", got.ReachablePoints, tt.want.reachablePoints)
+			}
+			if len(got.Regions) != tt.want.regions {
+				t.Fatalf("MapAnalysisDomain.Analyze() regions = %v, want %v", len(got.Regions), tt.want.regions)
+			}
+			if !got.Regions[0].Reachable {
+				t.Errorf("MapAnalysisDomain.Analyze() first region not reachable")
+			}
+			if !reflect.DeepEqual(got.Regions[0].ChokePoints, tt.want.chokePoints) {
+				t.Errorf("MapAnalysisDomain.Analyze() choke points = %v, want %v", got.Regions[0].ChokePoints, tt.want.chokePoints)
+			}
+			for i, points := range tt.want.enclosed {
+				if region := got.Regions[i+1]; region.Reachable || !reflect.DeepEqual(region.Points, points) {
+					t.Errorf("MapAnalysisDomain.Analyze() region = %v, want %v enclosed", region, points)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package domains
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/mars-rover-go/models"
+)
+
+// MissionPlannerDomain orders the sites of a mission with a nearest neighbour
+// tour improved by 2-opt, the legs between the sites being the cheapest
+// commands around the obstacles
+type MissionPlannerDomain struct {
+	gridDomain     IGridDomain
+	obstacleDomain IObstacleDomain
+	energy         models.EnergyDto
+}
+
+func NewMissionPlannerDomain(gridDomain IGridDomain, obstacleDomain IObstacleDomain, energy models.EnergyDto) IMissionPlannerDomain {
+	return &MissionPlannerDomain{
+		gridDomain:     gridDomain,
+		obstacleDomain: obstacleDomain,
+		energy:         energy,
+	}
+}
+
+// unreachableCost is the cost of a tour with a site unreachable from the
+// previous one, the directional obstacles letting the rover reach a site it
+// cannot leave
+const unreachableCost = math.MaxInt32
+
+// missionTour evaluates the tours of a mission, the searches from each
+// location being shared by the tours
+type missionTour struct {
+	planner  *MissionPlannerDomain
+	from     models.LocationDto
+	cost     func(cmd string) int
+	searches map[models.LocationDto]*commandSearch
+}
+
+func (m *MissionPlannerDomain) PlanMission(from models.LocationDto, sites []models.PointDto, objective models.MissionObjective) (models.MissionPlanDto, error) {
+	var cost func(cmd string) int
+	switch objective {
+	case models.MissionObjectiveCommands:
+		cost = func(string) int { return 1 }
+	case models.MissionObjectiveEnergy:
+		cost = m.energyOf
+	default:
+		return models.MissionPlanDto{}, fmt.Errorf("mission objective '%s' unknown", objective)
+	}
+
+	syncObstacles(m.obstacleDomain)
+
+	tour := &missionTour{
+		planner:  m,
+		from:     from,
+		cost:     cost,
+		searches: map[models.LocationDto]*commandSearch{},
+	}
+
+	// The sites are visited once, in the grid and free of obstacles
+	seen := map[models.PointDto]bool{}
+	remaining := []models.PointDto{}
+	for _, site := range sites {
+		if !m.gridDomain.IsPointInGrid(site) {
+			return models.MissionPlanDto{}, fmt.Errorf("site %d %d is out the grid", site.XPoint, site.YPoint)
+		}
+		if m.obstacleDomain.IsObstacle(site) {
+			return models.MissionPlanDto{}, fmt.Errorf("site %d %d is an obstacle", site.XPoint, site.YPoint)
+		}
+		if _, _, ok := tour.search(from).costTo(site); !ok {
+			return models.MissionPlanDto{}, fmt.Errorf("site %d %d is unreachable", site.XPoint, site.YPoint)
+		}
+		if !seen[site] {
+			seen[site] = true
+			remaining = append(remaining, site)
+		}
+	}
+
+	// Nearest neighbour tour. The sites left when none is reachable from the
+	// last one are appended as given, for the 2-opt to find a tour through them.
+	order := []models.PointDto{}
+	location := from
+	for len(remaining) > 0 {
+		next, nextCost := -1, 0
+		var nextLocation models.LocationDto
+		for i, site := range remaining {
+			siteLocation, siteCost, ok := tour.search(location).costTo(site)
+			if ok && (next < 0 || siteCost < nextCost) {
+				next, nextCost, nextLocation = i, siteCost, siteLocation
+			}
+		}
+		if next < 0 {
+			order = append(order, remaining...)
+			break
+		}
+
+		location = nextLocation
+		order = append(order, remaining[next])
+		remaining = append(remaining[:next], remaining[next+1:]...)
+	}
+
+	// 2-opt, reversing the parts of the tour until none lowers its cost
+	best := tour.costOf(order)
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < len(order)-1; i++ {
+			for j := i + 1; j < len(order); j++ {
+				candidate := reversed(order, i, j)
+				if candidateCost := tour.costOf(candidate); candidateCost < best {
+					order, best = candidate, candidateCost
+					improved = true
+				}
+			}
+		}
+	}
+
+	return tour.plan(order)
+}
+
+// energyOf returns the energy units of the command
+func (m *MissionPlannerDomain) energyOf(cmd string) int {
+	units := 0
+	switch strings.ToLower(cmd) {
+	case string(models.CommandForward):
+		units = m.energy.ForwardUnits
+	case string(models.CommandBackward):
+		units = m.energy.BackwardUnits
+	case string(models.CommandLeft):
+		units = m.energy.LeftUnits
+	case string(models.CommandRight):
+		units = m.energy.RightUnits
+	}
+
+	if units <= 0 {
+		return 1
+	}
+
+	return units
+}
+
+func (t *missionTour) search(from models.LocationDto) *commandSearch {
+	search, ok := t.searches[from]
+	if !ok {
+		search = searchLocationsByCost(t.planner.gridDomain, from, freeOf(t.planner.obstacleDomain), t.cost)
+		t.searches[from] = search
+	}
+
+	return search
+}
+
+// costOf returns the cost of the tour visiting the sites in order,
+// unreachableCost when a site is unreachable from the previous one
+func (t *missionTour) costOf(order []models.PointDto) int {
+	total := 0
+	location := t.from
+	for _, site := range order {
+		var cost int
+		var ok bool
+		location, cost, ok = t.search(location).costTo(site)
+		if !ok {
+			return unreachableCost
+		}
+		total += cost
+	}
+
+	return total
+}
+
+// plan returns the legs of the tour visiting the sites in order
+func (t *missionTour) plan(order []models.PointDto) (models.MissionPlanDto, error) {
+	plan := models.MissionPlanDto{
+		Order:    order,
+		Legs:     []models.MissionLegDto{},
+		Commands: []string{},
+		Location: t.from,
+	}
+
+	for _, site := range order {
+		search := t.search(plan.Location)
+		end, cost, ok := search.costTo(site)
+		if !ok {
+			return models.MissionPlanDto{}, fmt.Errorf("site %d %d is unreachable from %d %d, no tour visits every site", site.XPoint, site.YPoint, plan.Location.Point.XPoint, plan.Location.Point.YPoint)
+		}
+		commands := search.commandsTo(end)
+
+		plan.Legs = append(plan.Legs, models.MissionLegDto{Site: site, Commands: commands, Cost: cost})
+		plan.Commands = append(plan.Commands, commands...)
+		plan.Cost += cost
+		plan.Location = end
+	}
+
+	return plan, nil
+}
+
+// reversed returns a copy of the tour with the sites from i to j reversed
+func reversed(order []models.PointDto, i int, j int) []models.PointDto {
+	candidate := make([]models.PointDto, len(order))
+	copy(candidate, order)
+	for ; i < j; i, j = i+1, j-1 {
+		candidate[i], candidate[j] = candidate[j], candidate[i]
+	}
+
+	return candidate
+}
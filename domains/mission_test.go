@@ -0,0 +1,202 @@
+package domains
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestMissionPlannerDomain_PlanMission(t *testing.T) {
+	type args struct {
+		sites     []models.PointDto
+		objective models.MissionObjective
+	}
+
+	// 24 sites spread over the grid, listed in a poor order
+	spread := []models.PointDto{}
+	for i := 0; i < 24; i++ {
+		spread = append(spread, models.PointDto{XPoint: (i * 7) % 11, YPoint: (i * 5) % 11})
+	}
+	spread = removePoint(spread, models.PointDto{XPoint: 8, YPoint: 5})
+
+	tests := []struct {
+		name      string
+		obstacles []models.ObstacleDto
+		energy    models.EnergyDto
+		args      args
+		wantErr   bool
+	}{
+		{
+			name: "Sites visited with the fewest commands",
+			args: args{
+				sites:     []models.PointDto{{XPoint: 9, YPoint: 9}, {XPoint: 1, YPoint: 4}, {XPoint: 5, YPoint: 5}, {XPoint: 1, YPoint: 2}},
+				objective: models.MissionObjectiveCommands,
+			},
+		},
+		{
+			name:   "Sites visited with the least energy",
+			energy: models.EnergyDto{ForwardUnits: 1, BackwardUnits: 3, LeftUnits: 2, RightUnits: 2},
+			args: args{
+				sites:     []models.PointDto{{XPoint: 9, YPoint: 9}, {XPoint: 1, YPoint: 4}, {XPoint: 5, YPoint: 5}, {XPoint: 1, YPoint: 2}},
+				objective: models.MissionObjectiveEnergy,
+			},
+		},
+		{
+			name: "Many sites",
+			args: args{sites: spread, objective: models.MissionObjectiveCommands},
+		},
+		{
+			name: "Site repeated",
+			args: args{
+				sites:     []models.PointDto{{XPoint: 3, YPoint: 3}, {XPoint: 3, YPoint: 3}},
+				objective: models.MissionObjectiveCommands,
+			},
+		},
+		{
+			name:    "Site on an obstacle",
+			args:    args{sites: []models.PointDto{{XPoint: 2, YPoint: 6}}, objective: models.MissionObjectiveCommands},
+			wantErr: true,
+		},
+		{
+			name:    "Site out the grid",
+			args:    args{sites: []models.PointDto{{XPoint: 11, YPoint: 6}}, objective: models.MissionObjectiveCommands},
+			wantErr: true,
+		},
+		{
+			name: "Site enclosed by obstacles",
+			obstacles: []models.ObstacleDto{
+				{Point: models.PointDto{XPoint: 4, YPoint: 5}},
+				{Point: models.PointDto{XPoint: 6, YPoint: 5}},
+				{Point: models.PointDto{XPoint: 5, YPoint: 4}},
+				{Point: models.PointDto{XPoint: 5, YPoint: 6}},
+			},
+			args:    args{sites: []models.PointDto{{XPoint: 5, YPoint: 5}}, objective: models.MissionObjectiveCommands},
+			wantErr: true,
+		},
+		{
+			name:    "Objective unknown",
+			args:    args{sites: []models.PointDto{{XPoint: 3, YPoint: 3}}, objective: "time"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rover := newRoverDomainMocked()
+			if tt.obstacles != nil {
+				rover.obstacleDomain = &ObstacleDomain{obstacles: tt.obstacles}
+			}
+			m := NewMissionPlannerDomain(rover.gridDomain, rover.obstacleDomain, tt.energy)
+
+			got, err := m.PlanMission(rover.Location(), tt.args.sites, tt.args.objective)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MissionPlannerDomain.PlanMission() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			// The rover executes the commands and passes on every site
+			plan, err := rover.Plan(got.Commands)
+			if err != nil {
+				t.Fatalf("RoverDomain.Plan() error = %v", err)
+			}
+			if plan.Location != got.Location {
+				t.Errorf("MissionPlannerDomain.PlanMission() location = %v, want %v", got.Location, plan.Location)
+			}
+			passed := map[models.PointDto]bool{rover.Location().Point: true}
+			for _, event := range plan.Events {
+				passed[event.Location.Point] = true
+			}
+			for _, site := range tt.args.sites {
+				if !passed[site] {
+					t.Errorf("MissionPlannerDomain.PlanMission() site %v not visited", site)
+				}
+			}
+
+			sites := map[models.PointDto]bool{}
+			for _, site := range tt.args.sites {
+				sites[site] = true
+			}
+			if len(got.Order) != len(sites) || len(got.Legs) != len(sites) {
+				t.Errorf("MissionPlannerDomain.PlanMission() order = %v, want the %d sites once", got.Order, len(sites))
+			}
+
+			cost := 0
+			for _, cmd := range got.Commands {
+				if tt.args.objective == models.MissionObjectiveEnergy {
+					cost += m.(*MissionPlannerDomain).energyOf(cmd)
+				} else {
+					cost++
+				}
+			}
+			if got.Cost != cost {
+				t.Errorf("MissionPlannerDomain.PlanMission() cost = %v, want %v", got.Cost, cost)
+			}
+
+			// The tour is not worse than the sites in the order given
+			tour := &missionTour{planner: m.(*MissionPlannerDomain), from: rover.Location(), cost: func(string) int { return 1 }, searches: map[models.LocationDto]*commandSearch{}}
+			if tt.args.objective == models.MissionObjectiveEnergy {
+				tour.cost = m.(*MissionPlannerDomain).energyOf
+			}
+			if ordered := tour.costOf(tt.args.sites); ordered < got.Cost {
+				t.Errorf("MissionPlannerDomain.PlanMission() cost = %v, greater than the sites in order %v", got.Cost, ordered)
+			}
+		})
+	}
+}
+
+func TestMissionPlannerDomain_PlanMissionTerrain(t *testing.T) {
+	// The rover drives down into the pits and cannot climb out of them
+	grid := models.GridDto{XPointMax: 2, YPointMax: 1}
+	from := models.LocationDto{Point: models.PointDto{XPoint: 0, YPoint: 1}, Direction: models.DirectionNorth}
+
+	tests := []struct {
+		name       string
+		elevations [][]float64
+		sites      []models.PointDto
+		wantOrder  []models.PointDto
+		wantErr    bool
+	}{
+		{
+			name:       "Pit visited last",
+			elevations: [][]float64{{0, -10, 0}, {0, 0, 0}},
+			sites:      []models.PointDto{{XPoint: 1, YPoint: 0}, {XPoint: 2, YPoint: 0}},
+			wantOrder:  []models.PointDto{{XPoint: 2, YPoint: 0}, {XPoint: 1, YPoint: 0}},
+		},
+		{
+			name:       "Two pits",
+			elevations: [][]float64{{-10, 0, -10}, {0, 0, 0}},
+			sites:      []models.PointDto{{XPoint: 0, YPoint: 0}, {XPoint: 2, YPoint: 0}},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terrain, err := NewTerrainDomain(grid, models.HeightmapDto{Elevations: tt.elevations}, models.TerrainDto{CellSizeMeters: 1, MaxSlope: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			m := NewMissionPlannerDomain(NewGridDomain(grid), terrain, models.EnergyDto{})
+
+			got, err := m.PlanMission(from, tt.sites, models.MissionObjectiveCommands)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MissionPlannerDomain.PlanMission() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got.Order, tt.wantOrder) {
+				t.Errorf("MissionPlannerDomain.PlanMission() order = %v, want %v", got.Order, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func removePoint(points []models.PointDto, point models.PointDto) []models.PointDto {
+	kept := []models.PointDto{}
+	for _, p := range points {
+		if p != point {
+			kept = append(kept, p)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,119 @@
+package domains
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mars-rover-go/models"
+)
+
+// MovingObstacleDomain answers with the obstacles positions at the current
+// clock time. The random paths are generated from the seed, so the same seed
+// always gives the same paths.
+type MovingObstacleDomain struct {
+	clock      IClockDomain
+	gridDomain IGridDomain
+	obstacles  []models.MovingObstacleDto
+
+	mu sync.Mutex
+	// walks holds the current point of the random path of each obstacle,
+	// reached after the steps
+	walks []models.PointDto
+	steps []int
+	rngs  []*rand.Rand
+}
+
+func NewMovingObstacleDomain(clock IClockDomain, gridDomain IGridDomain, moving models.MovingObstaclesDto) IObstacleDomain {
+	o := &MovingObstacleDomain{
+		clock:      clock,
+		gridDomain: gridDomain,
+		obstacles:  moving.Obstacles,
+		walks:      make([]models.PointDto, len(moving.Obstacles)),
+		steps:      make([]int, len(moving.Obstacles)),
+		rngs:       make([]*rand.Rand, len(moving.Obstacles)),
+	}
+
+	for i, obstacle := range moving.Obstacles {
+		o.walks[i] = obstacle.Start
+		o.rngs[i] = rand.New(rand.NewSource(moving.Seed + int64(i)))
+	}
+
+	return o
+}
+
+func (o *MovingObstacleDomain) IsObstacle(point models.PointDto) bool {
+	for _, position := range o.Positions() {
+		if position == point {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Positions returns the obstacles positions at the current clock time
+func (o *MovingObstacleDomain) Positions() []models.PointDto {
+	now := o.clock.Now()
+
+	positions := make([]models.PointDto, len(o.obstacles))
+	for i := range o.obstacles {
+		positions[i] = o.positionAt(i, now)
+	}
+
+	return positions
+}
+
+func (o *MovingObstacleDomain) positionAt(i int, now time.Duration) models.PointDto {
+	obstacle := o.obstacles[i]
+	if obstacle.StepMilliseconds <= 0 {
+		return obstacle.Start
+	}
+
+	step := int(now / (time.Duration(obstacle.StepMilliseconds) * time.Millisecond))
+
+	if len(obstacle.Path) > 0 {
+		k := step % (len(obstacle.Path) + 1)
+		if k == 0 {
+			return obstacle.Start
+		}
+		return obstacle.Path[k-1]
+	}
+
+	return o.walk(i, step)
+}
+
+// walk returns the point of the random path after the number of steps. The
+// clock only goes forward, so only the current point is kept: a step already
+// passed returns the current point.
+func (o *MovingObstacleDomain) walk(i int, step int) models.PointDto {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for ; o.steps[i] < step; o.steps[i]++ {
+		o.walks[i] = o.randomStep(o.rngs[i], o.walks[i])
+	}
+
+	return o.walks[i]
+}
+
+// randomStep stays or moves one point in a random direction, staying in the grid
+func (o *MovingObstacleDomain) randomStep(rng *rand.Rand, point models.PointDto) models.PointDto {
+	directions := []models.Direction{models.DirectionNorth, models.DirectionEast, models.DirectionSouth, models.DirectionWest}
+
+	n := rng.Intn(len(directions) + 1)
+	if n == len(directions) {
+		return point
+	}
+
+	next := nextPoint(models.LocationDto{Point: point, Direction: directions[n]}, models.MoveTypeForward)
+	if !o.gridDomain.IsPointInGrid(next) {
+		wrapped, isWrapped := o.gridDomain.WrapPoint(next)
+		if !isWrapped {
+			return point
+		}
+		next = wrapped
+	}
+
+	return next
+}
@@ -0,0 +1,147 @@
+package domains
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestMovingObstacleDomain_IsObstacle(t *testing.T) {
+	type args struct {
+		at    time.Duration
+		point models.PointDto
+	}
+
+	moving := models.MovingObstaclesDto{
+		Obstacles: []models.MovingObstacleDto{
+			{
+				Start:            models.PointDto{XPoint: 1, YPoint: 1},
+				Path:             []models.PointDto{{XPoint: 2, YPoint: 1}, {XPoint: 3, YPoint: 1}},
+				StepMilliseconds: 1000,
+			},
+			{
+				Start: models.PointDto{XPoint: 5, YPoint: 5},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "Obstacle at the start",
+			args: args{at: 500 * time.Millisecond, point: models.PointDto{XPoint: 1, YPoint: 1}},
+			want: true,
+		},
+		{
+			name: "Obstacle moved away",
+			args: args{at: 1000 * time.Millisecond, point: models.PointDto{XPoint: 1, YPoint: 1}},
+			want: false,
+		},
+		{
+			name: "Obstacle along the path",
+			args: args{at: 2500 * time.Millisecond, point: models.PointDto{XPoint: 3, YPoint: 1}},
+			want: true,
+		},
+		{
+			name: "Obstacle back at the start",
+			args: args{at: 3000 * time.Millisecond, point: models.PointDto{XPoint: 1, YPoint: 1}},
+			want: true,
+		},
+		{
+			name: "Obstacle without step not moving",
+			args: args{at: time.Hour, point: models.PointDto{XPoint: 5, YPoint: 5}},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewSimulationClockDomain(time.Millisecond)
+			o := NewMovingObstacleDomain(clock, NewGridDomain(models.GridDto{XPointMax: 10, YPointMax: 10}), moving)
+
+			clock.RunUntil(tt.args.at)
+			if got := o.IsObstacle(tt.args.point); got != tt.want {
+				t.Errorf("MovingObstacleDomain.IsObstacle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMovingObstacleDomain_RandomPaths(t *testing.T) {
+	grid := NewGridDomain(models.GridDto{XPointMax: 3, YPointMax: 3})
+	moving := models.MovingObstaclesDto{
+		Seed: 42,
+		Obstacles: []models.MovingObstacleDto{
+			{Start: models.PointDto{XPoint: 0, YPoint: 0}, StepMilliseconds: 100},
+			{Start: models.PointDto{XPoint: 3, YPoint: 3}, StepMilliseconds: 250},
+		},
+	}
+
+	paths := func() [][]models.PointDto {
+		clock := NewSimulationClockDomain(100 * time.Millisecond)
+		o := NewMovingObstacleDomain(clock, grid, moving).(*MovingObstacleDomain)
+
+		paths := [][]models.PointDto{}
+		for i := 0; i < 50; i++ {
+			positions := o.Positions()
+			for _, point := range positions {
+				if !grid.IsPointInGrid(point) {
+					t.Fatalf("MovingObstacleDomain obstacle out of the grid at %v", point)
+				}
+			}
+			paths = append(paths, positions)
+			clock.Tick()
+		}
+
+		return paths
+	}
+
+	first, second := paths(), paths()
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("MovingObstacleDomain random paths = %v, then %v with the same seed", first, second)
+	}
+	if reflect.DeepEqual(first[0], first[len(first)-1]) && reflect.DeepEqual(first[0], first[len(first)/2]) {
+		t.Errorf("MovingObstacleDomain random paths not moving: %v", first)
+	}
+}
+
+func TestMovingObstacleDomain_BlocksRover(t *testing.T) {
+	clock := NewSimulationClockDomain(time.Millisecond)
+	rover := newRoverDomainMocked()
+	rover.clock = clock
+	rover.obstacleDomain = NewCompositeObstacleDomain(
+		rover.obstacleDomain,
+		NewMovingObstacleDomain(clock, rover.gridDomain, models.MovingObstaclesDto{
+			Obstacles: []models.MovingObstacleDto{
+				{
+					Start:            models.PointDto{XPoint: 5, YPoint: 3},
+					Path:             []models.PointDto{{XPoint: 1, YPoint: 3}},
+					StepMilliseconds: 1500,
+				},
+			},
+		}),
+	)
+
+	var location models.LocationDto
+	var err error
+	clock.Go(func() {
+		location, err = rover.ExecuteTimedCommands(context.Background(), []string{"f", "f"}, models.CommandDurationDto{ForwardMilliseconds: 1000})
+	})
+	clock.Run()
+
+	// The second move completes at 2s, once the obstacle reached (1,3)
+	want := models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 2}, Direction: models.DirectionNorth}
+	if location != want {
+		t.Errorf("RoverDomain.ExecuteTimedCommands() = %v, want %v", location, want)
+	}
+	var batchCancelled *BatchCancelledError
+	if err == nil || errors.As(err, &batchCancelled) {
+		t.Errorf("RoverDomain.ExecuteTimedCommands() error = %v, want obstacle detected", err)
+	}
+}
@@ -0,0 +1,90 @@
+package domains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/utils"
+)
+
+// NavigatorDomain drives the rover back to safety: home is its starting
+// location, the paths are searched on the current grid and obstacles
+type NavigatorDomain struct {
+	rover          IRoverDomain
+	gridDomain     IGridDomain
+	obstacleDomain IObstacleDomain
+	home           models.LocationDto
+	retreat        models.RetreatDto
+}
+
+func NewNavigatorDomain(rover IRoverDomain, gridDomain IGridDomain, obstacleDomain IObstacleDomain, home models.LocationDto, retreat models.RetreatDto) INavigatorDomain {
+	return &NavigatorDomain{
+		rover:          rover,
+		gridDomain:     gridDomain,
+		obstacleDomain: obstacleDomain,
+		home:           home,
+		retreat:        retreat,
+	}
+}
+
+func (n *NavigatorDomain) ExecuteTimedCommands(ctx context.Context, commands []string, durations models.CommandDurationDto) (models.LocationDto, error) {
+	location, err := n.rover.ExecuteTimedCommands(ctx, commands, durations)
+
+	var obstacleErr *ObstacleDetectedError
+	if !n.retreat.Auto || !errors.As(err, &obstacleErr) {
+		return location, err
+	}
+
+	location, retreatErr := n.Retreat(ctx, durations)
+	if retreatErr != nil {
+		return location, fmt.Errorf("%w - retreat failed: %v", err, retreatErr)
+	}
+
+	return location, fmt.Errorf("%w - retreated to %s", err, utils.LocationToString(location))
+}
+
+func (n *NavigatorDomain) ReturnHome(ctx context.Context, durations models.CommandDurationDto) (models.LocationDto, error) {
+	return n.rover.ExecutePlannedCommands(ctx, func(from models.LocationDto) ([]string, error) {
+		search := n.search(from)
+		if _, ok := search.parents[n.home]; !ok {
+			return nil, fmt.Errorf("starting location %s is unreachable", utils.LocationToString(n.home))
+		}
+
+		return search.commandsTo(n.home), nil
+	}, durations)
+}
+
+func (n *NavigatorDomain) Retreat(ctx context.Context, durations models.CommandDurationDto) (models.LocationDto, error) {
+	if len(n.retreat.SafeZones) == 0 {
+		return n.ReturnHome(ctx, durations)
+	}
+
+	isSafe := func(point models.PointDto) bool {
+		for _, zone := range n.retreat.SafeZones {
+			if zone == point {
+				return true
+			}
+		}
+		return false
+	}
+
+	return n.rover.ExecutePlannedCommands(ctx, func(from models.LocationDto) ([]string, error) {
+		search := n.search(from)
+		zone, ok := search.nearest(isSafe)
+		if !ok {
+			return nil, fmt.Errorf("no safe zone is reachable")
+		}
+
+		return search.commandsTo(zone), nil
+	}, durations)
+}
+
+// search searches the locations reachable from the rover location, on the
+// obstacles synced with the events of the batches run before
+func (n *NavigatorDomain) search(from models.LocationDto) *commandSearch {
+	syncObstacles(n.obstacleDomain)
+
+	return searchLocations(n.gridDomain, from, freeOf(n.obstacleDomain), nil)
+}
@@ -0,0 +1,190 @@
+package domains
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestNavigatorDomain_ReturnHome(t *testing.T) {
+	home := models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorth}
+
+	tests := []struct {
+		name      string
+		commands  []string
+		obstacles []models.PointDto
+		want      models.LocationDto
+		wantErr   bool
+	}{
+		{
+			name:     "Home after a drive",
+			commands: []string{"f", "f", "r", "f", "f", "f"},
+			want:     home,
+		},
+		{
+			name:     "Home around an obstacle",
+			commands: []string{"f", "f", "f", "f", "r", "f"},
+			obstacles: []models.PointDto{
+				{XPoint: 2, YPoint: 4},
+				{XPoint: 1, YPoint: 4},
+			},
+			want: home,
+		},
+		{
+			name:     "Home enclosed by obstacles",
+			commands: []string{"f", "f", "f"},
+			obstacles: []models.PointDto{
+				{XPoint: 0, YPoint: 1},
+				{XPoint: 2, YPoint: 1},
+				{XPoint: 1, YPoint: 0},
+				{XPoint: 1, YPoint: 2},
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 4}, Direction: models.DirectionNorth},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, rover, obstacles := newNavigatorDomainMocked(models.RetreatDto{})
+			if _, err := rover.ExecuteCommands(tt.commands); err != nil {
+				t.Fatal(err)
+			}
+			for _, point := range tt.obstacles {
+				obstacles.Add(models.ObstacleDto{Point: point})
+			}
+
+			got, err := n.ReturnHome(context.Background(), models.CommandDurationDto{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NavigatorDomain.ReturnHome() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NavigatorDomain.ReturnHome() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNavigatorDomain_Retreat(t *testing.T) {
+	tests := []struct {
+		name      string
+		safeZones []models.PointDto
+		want      models.PointDto
+	}{
+		{
+			name:      "Nearest safe zone",
+			safeZones: []models.PointDto{{XPoint: 9, YPoint: 9}, {XPoint: 4, YPoint: 2}, {XPoint: 0, YPoint: 10}},
+			want:      models.PointDto{XPoint: 4, YPoint: 2},
+		},
+		{
+			name: "Home without safe zone",
+			want: models.PointDto{XPoint: 1, YPoint: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, rover, _ := newNavigatorDomainMocked(models.RetreatDto{SafeZones: tt.safeZones})
+			if _, err := rover.ExecuteCommands([]string{"f", "f", "r", "f", "f"}); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := n.Retreat(context.Background(), models.CommandDurationDto{})
+			if err != nil {
+				t.Fatalf("NavigatorDomain.Retreat() error = %v", err)
+			}
+			if got.Point != tt.want {
+				t.Errorf("NavigatorDomain.Retreat() = %v, want %v", got.Point, tt.want)
+			}
+		})
+	}
+}
+
+func TestNavigatorDomain_ExecuteTimedCommands(t *testing.T) {
+	safeZone := models.PointDto{XPoint: 3, YPoint: 3}
+
+	tests := []struct {
+		name     string
+		retreat  models.RetreatDto
+		commands []string
+		want     models.PointDto
+		wantErr  bool
+	}{
+		{
+			name:     "Batch completed",
+			retreat:  models.RetreatDto{SafeZones: []models.PointDto{safeZone}, Auto: true},
+			commands: []string{"f", "f"},
+			want:     models.PointDto{XPoint: 1, YPoint: 3},
+		},
+		{
+			name:     "Retreat after an obstacle",
+			retreat:  models.RetreatDto{SafeZones: []models.PointDto{safeZone}, Auto: true},
+			commands: []string{"r", "f", "l", "f", "f", "f", "f", "f"},
+			want:     safeZone,
+			wantErr:  true,
+		},
+		{
+			name:     "Obstacle without auto retreat",
+			retreat:  models.RetreatDto{SafeZones: []models.PointDto{safeZone}},
+			commands: []string{"r", "f", "l", "f", "f", "f", "f", "f"},
+			want:     models.PointDto{XPoint: 2, YPoint: 5},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, _, _ := newNavigatorDomainMocked(tt.retreat)
+
+			got, err := n.ExecuteTimedCommands(context.Background(), tt.commands, models.CommandDurationDto{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NavigatorDomain.ExecuteTimedCommands() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Point != tt.want {
+				t.Errorf("NavigatorDomain.ExecuteTimedCommands() = %v, want %v", got.Point, tt.want)
+			}
+
+			var obstacleErr *ObstacleDetectedError
+			if tt.wantErr && !errors.As(err, &obstacleErr) {
+				t.Errorf("NavigatorDomain.ExecuteTimedCommands() error = %v, want an obstacle detected", err)
+			}
+			if retreated := err != nil && strings.Contains(err.Error(), "retreated"); retreated != (tt.wantErr && tt.retreat.Auto) {
+				t.Errorf("NavigatorDomain.ExecuteTimedCommands() error = %v, retreated %v", err, retreated)
+			}
+		})
+	}
+}
+
+func TestNavigatorDomain_ReturnHomeKnownMap(t *testing.T) {
+	eventStore := NewEventStoreDomain(DefaultSnapshotInterval)
+	rover := newRoverDomainMocked()
+	rover.eventStore = eventStore
+	home := rover.Location()
+
+	knownMap := NewKnownMapDomain(rover.gridDomain, rover.obstacleDomain, models.SensorDto{}, 0, eventStore)
+	n := NewNavigatorDomain(&rover, rover.gridDomain, knownMap, home, models.RetreatDto{})
+
+	// The obstacle at (2,6) is only known from the event of the batch it aborts
+	if _, err := rover.ExecuteCommands([]string{"r", "f", "l", "f", "f", "f", "f", "f"}); err == nil {
+		t.Fatalf("RoverDomain.ExecuteCommands() error = nil, want obstacle detected")
+	}
+
+	got, err := n.ReturnHome(context.Background(), models.CommandDurationDto{})
+	if err != nil || got != home {
+		t.Errorf("NavigatorDomain.ReturnHome() = %v, %v, want %v", got, err, home)
+	}
+	if !knownMap.IsObstacle(models.PointDto{XPoint: 2, YPoint: 6}) {
+		t.Errorf("NavigatorDomain.ReturnHome() planned without syncing the known map")
+	}
+}
+
+func newNavigatorDomainMocked(retreat models.RetreatDto) (INavigatorDomain, *RoverDomain, IObstacleMapDomain) {
+	rover := newRoverDomainMocked()
+	obstacles := NewObstacleDomain([]models.ObstacleDto{
+		{Point: models.PointDto{XPoint: 2, YPoint: 6}},
+		{Point: models.PointDto{XPoint: 8, YPoint: 5}},
+	})
+	rover.obstacleDomain = obstacles
+
+	return NewNavigatorDomain(&rover, rover.gridDomain, obstacles, rover.Location(), retreat), &rover, obstacles
+}
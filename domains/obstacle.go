@@ -1,16 +1,31 @@
 package domains
 
-import "github.com/mars-rover-go/models"
+import (
+	"sync"
 
+	"github.com/mars-rover-go/models"
+)
+
+// ObstacleDomain is safe for concurrent use, obstacles can be edited while
+// the rover moves
 type ObstacleDomain struct {
+	mu        sync.RWMutex
 	obstacles []models.ObstacleDto
 }
 
-func NewObstacleDomain(obstacles []models.ObstacleDto) IObstacleDomain {
-	return &ObstacleDomain{obstacles}
+func NewObstacleDomain(obstacles []models.ObstacleDto) IObstacleMapDomain {
+	return &ObstacleDomain{obstacles: obstacles}
 }
 
 func (o *ObstacleDomain) IsObstacle(point models.PointDto) bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return o.isObstacle(point)
+}
+
+// isObstacle checks if the point is an obstacle, the obstacles must be locked
+func (o *ObstacleDomain) isObstacle(point models.PointDto) bool {
 	for _, ob := range o.obstacles {
 		if ob.Point.XPoint == point.XPoint && ob.Point.YPoint == point.YPoint {
 			// obstacle detected
@@ -20,3 +35,88 @@ func (o *ObstacleDomain) IsObstacle(point models.PointDto) bool {
 
 	return false
 }
+
+func (o *ObstacleDomain) Add(obstacles ...models.ObstacleDto) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, ob := range obstacles {
+		if !o.isObstacle(ob.Point) {
+			o.obstacles = append(o.obstacles, ob)
+		}
+	}
+}
+
+func (o *ObstacleDomain) Remove(obstacles ...models.ObstacleDto) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	kept := make([]models.ObstacleDto, 0, len(o.obstacles))
+	for _, ob := range o.obstacles {
+		removed := false
+		for _, r := range obstacles {
+			if ob.Point == r.Point {
+				removed = true
+				break
+			}
+		}
+
+		if !removed {
+			kept = append(kept, ob)
+		}
+	}
+	o.obstacles = kept
+}
+
+func (o *ObstacleDomain) Obstacles() []models.ObstacleDto {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return append([]models.ObstacleDto{}, o.obstacles...)
+}
+
+// CompositeObstacleDomain finds an obstacle where any of its obstacle domains does
+type CompositeObstacleDomain struct {
+	obstacleDomains []IObstacleDomain
+}
+
+func NewCompositeObstacleDomain(obstacleDomains ...IObstacleDomain) IObstacleDomain {
+	return &CompositeObstacleDomain{obstacleDomains}
+}
+
+func (o *CompositeObstacleDomain) IsObstacle(point models.PointDto) bool {
+	for _, obstacleDomain := range o.obstacleDomains {
+		if obstacleDomain.IsObstacle(point) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o *CompositeObstacleDomain) IsObstacleFrom(from models.PointDto, point models.PointDto) bool {
+	for _, obstacleDomain := range o.obstacleDomains {
+		if isObstacleFrom(obstacleDomain, from, point) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isObstacleFrom checks if the point is an obstacle for the rover coming from
+// the neighbour point, whether the obstacle domain is directional or not
+// syncObstacles syncs the obstacle domain built from the rover events
+func syncObstacles(obstacleDomain IObstacleDomain) {
+	if synced, ok := obstacleDomain.(ISyncedObstacleDomain); ok {
+		synced.Sync()
+	}
+}
+
+func isObstacleFrom(obstacleDomain IObstacleDomain, from models.PointDto, point models.PointDto) bool {
+	if directional, ok := obstacleDomain.(IDirectionalObstacleDomain); ok {
+		return directional.IsObstacleFrom(from, point)
+	}
+
+	return obstacleDomain.IsObstacle(point)
+}
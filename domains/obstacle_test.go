@@ -1,6 +1,7 @@
 package domains
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/mars-rover-go/models"
@@ -12,7 +13,7 @@ func TestObstacleDomain_isObstacle(t *testing.T) {
 	}
 
 	obstacleDomain := &ObstacleDomain{
-		[]models.ObstacleDto{
+		obstacles: []models.ObstacleDto{
 			{Point: models.PointDto{XPoint: 2, YPoint: 6}},
 			{Point: models.PointDto{XPoint: 8, YPoint: 5}},
 		},
@@ -49,3 +50,34 @@ func TestObstacleDomain_isObstacle(t *testing.T) {
 		})
 	}
 }
+
+func TestObstacleDomain_Edit(t *testing.T) {
+	tests := []struct {
+		name    string
+		added   []models.ObstacleDto
+		removed []models.ObstacleDto
+		want    []models.ObstacleDto
+	}{
+		{
+			name:  "Obstacles added",
+			added: []models.ObstacleDto{{Point: models.PointDto{XPoint: 1, YPoint: 1}}, {Point: models.PointDto{XPoint: 2, YPoint: 6}}},
+			want:  []models.ObstacleDto{{Point: models.PointDto{XPoint: 2, YPoint: 6}}, {Point: models.PointDto{XPoint: 1, YPoint: 1}}},
+		},
+		{
+			name:    "Obstacles removed",
+			removed: []models.ObstacleDto{{Point: models.PointDto{XPoint: 2, YPoint: 6}}, {Point: models.PointDto{XPoint: 3, YPoint: 3}}},
+			want:    []models.ObstacleDto{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewObstacleDomain([]models.ObstacleDto{{Point: models.PointDto{XPoint: 2, YPoint: 6}}})
+			o.Add(tt.added...)
+			o.Remove(tt.removed...)
+
+			if got := o.Obstacles(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ObstacleDomain.Obstacles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package domains
+
+import (
+	"container/heap"
+	"strings"
+
+	"github.com/mars-rover-go/models"
+)
+
+// searchCommands are the commands tried by the path searches, in order
+var searchCommands = []string{
+	string(models.CommandForward),
+	string(models.CommandLeft),
+	string(models.CommandRight),
+	string(models.CommandBackward),
+}
+
+// commandSearch is a search of the rover locations reachable from a
+// location, each command costing one step unless searched by cost
+type commandSearch struct {
+	from    models.LocationDto
+	parents map[models.LocationDto]searchStep
+	// costs holds the cost of the commands leading to each location reached
+	costs map[models.LocationDto]int
+	// reached holds the locations in the order they are reached, the
+	// nearest first
+	reached []models.LocationDto
+}
+
+type searchStep struct {
+	from    models.LocationDto
+	command string
+}
+
+// searchLocations searches the locations reachable from the location,
+// entering only the points allowed by canEnter from the previous point. The search does not go on
+// from the points where stopAt is true.
+func searchLocations(gridDomain IGridDomain, from models.LocationDto, canEnter func(from models.PointDto, point models.PointDto) bool, stopAt func(models.PointDto) bool) *commandSearch {
+	s := newCommandSearch(from)
+	for i := 0; i < len(s.reached); i++ {
+		location := s.reached[i]
+		if i > 0 && stopAt != nil && stopAt(location.Point) {
+			continue
+		}
+		s.expand(gridDomain, location, canEnter)
+	}
+
+	return s
+}
+
+// searchLocationsTo searches the locations as searchLocations does until one
+// on the target point is reached, the search holding the nearest one
+func searchLocationsTo(gridDomain IGridDomain, from models.LocationDto, canEnter func(from models.PointDto, point models.PointDto) bool, target models.PointDto) *commandSearch {
+	s := newCommandSearch(from)
+	for i := 0; i < len(s.reached); i++ {
+		location := s.reached[i]
+		if location.Point == target {
+			break
+		}
+		s.expand(gridDomain, location, canEnter)
+	}
+
+	return s
+}
+
+func newCommandSearch(from models.LocationDto) *commandSearch {
+	return &commandSearch{
+		from:    from,
+		parents: map[models.LocationDto]searchStep{from: {}},
+		costs:   map[models.LocationDto]int{from: 0},
+		reached: []models.LocationDto{from},
+	}
+}
+
+// expand reaches the locations one command away from the location
+func (s *commandSearch) expand(gridDomain IGridDomain, location models.LocationDto, canEnter func(from models.PointDto, point models.PointDto) bool) {
+	for _, cmd := range searchCommands {
+		next, ok := nextLocation(gridDomain, location, cmd)
+		if !ok || !canStep(gridDomain, location, next, cmd, canEnter) {
+			continue
+		}
+		if _, seen := s.parents[next]; seen {
+			continue
+		}
+
+		s.parents[next] = searchStep{from: location, command: cmd}
+		s.costs[next] = s.costs[location] + 1
+		s.reached = append(s.reached, next)
+	}
+}
+
+// searchLocationsByCost searches the locations reachable from the location,
+// entering only the points allowed by canEnter from the previous point, with the cheapest commands
+// given their cost. The locations are reached in order of cost.
+func searchLocationsByCost(gridDomain IGridDomain, from models.LocationDto, canEnter func(from models.PointDto, point models.PointDto) bool, cost func(cmd string) int) *commandSearch {
+	s := &commandSearch{
+		from:    from,
+		parents: map[models.LocationDto]searchStep{},
+		costs:   map[models.LocationDto]int{},
+		reached: []models.LocationDto{},
+	}
+
+	queue := searchQueue{}
+	seq := 0
+	pending := map[models.LocationDto]int{from: 0}
+	heap.Push(&queue, &searchItem{location: from, cost: 0, seq: seq})
+
+	for queue.Len() > 0 {
+		item := heap.Pop(&queue).(*searchItem)
+		if _, done := s.costs[item.location]; done {
+			continue
+		}
+		s.costs[item.location] = item.cost
+		s.reached = append(s.reached, item.location)
+
+		for _, cmd := range searchCommands {
+			next, ok := nextLocation(gridDomain, item.location, cmd)
+			if !ok || !canStep(gridDomain, item.location, next, cmd, canEnter) {
+				continue
+			}
+			if _, done := s.costs[next]; done {
+				continue
+			}
+
+			nextCost := item.cost + cost(cmd)
+			if known, ok := pending[next]; ok && known <= nextCost {
+				continue
+			}
+			pending[next] = nextCost
+			s.parents[next] = searchStep{from: item.location, command: cmd}
+			seq++
+			heap.Push(&queue, &searchItem{location: next, cost: nextCost, seq: seq})
+		}
+	}
+
+	return s
+}
+
+// canStep checks if the rover can go from the location to the next one with
+// the command, entering the point without cutting a corner
+func canStep(gridDomain IGridDomain, location models.LocationDto, next models.LocationDto, cmd string, canEnter func(from models.PointDto, point models.PointDto) bool) bool {
+	if next.Point == location.Point {
+		return true
+	}
+	if !canEnter(location.Point, next.Point) {
+		return false
+	}
+
+	moveType := models.MoveTypeForward
+	if strings.ToLower(cmd) == string(models.CommandBackward) {
+		moveType = models.MoveTypeBackward
+	}
+	_, cut := cutsCorner(gridDomain, location, moveType, canEnter)
+
+	return !cut
+}
+
+// freeOf returns the canEnter of the searches avoiding the obstacles
+func freeOf(obstacleDomain IObstacleDomain) func(from models.PointDto, point models.PointDto) bool {
+	return func(from models.PointDto, point models.PointDto) bool {
+		return !isObstacleFrom(obstacleDomain, from, point)
+	}
+}
+
+// nearest returns the nearest location reached on a point matching isGoal
+func (s *commandSearch) nearest(isGoal func(models.PointDto) bool) (models.LocationDto, bool) {
+	for _, location := range s.reached {
+		if isGoal(location.Point) {
+			return location, true
+		}
+	}
+
+	return models.LocationDto{}, false
+}
+
+// goals returns the points matching isGoal with the nearest location
+// reaching each one, in order of distance
+func (s *commandSearch) goals(isGoal func(models.PointDto) bool) []models.LocationDto {
+	seen := map[models.PointDto]bool{}
+	goals := []models.LocationDto{}
+
+	for _, location := range s.reached {
+		if !seen[location.Point] && isGoal(location.Point) {
+			seen[location.Point] = true
+			goals = append(goals, location)
+		}
+	}
+
+	return goals
+}
+
+// isReached checks if the point is reachable in any direction
+func (s *commandSearch) isReached(point models.PointDto) bool {
+	_, ok := s.nearest(func(p models.PointDto) bool { return p == point })
+	return ok
+}
+
+// commandsTo returns the commands leading to the reached location
+func (s *commandSearch) commandsTo(location models.LocationDto) []string {
+	commands := []string{}
+
+	for location != s.from {
+		step := s.parents[location]
+		commands = append(commands, step.command)
+		location = step.from
+	}
+
+	for i, j := 0, len(commands)-1; i < j; i, j = i+1, j-1 {
+		commands[i], commands[j] = commands[j], commands[i]
+	}
+
+	return commands
+}
+
+// costTo returns the cost of the commands leading to the nearest location
+// reached on the point. Returns false when the point is not reached.
+func (s *commandSearch) costTo(point models.PointDto) (models.LocationDto, int, bool) {
+	location, ok := s.nearest(func(p models.PointDto) bool { return p == point })
+	if !ok {
+		return models.LocationDto{}, 0, false
+	}
+
+	return location, s.costs[location], true
+}
+
+// nextLocation returns the location after the command, as the rover would
+// execute it without obstacles. Returns false when the move leaves the grid.
+func nextLocation(gridDomain IGridDomain, location models.LocationDto, cmd string) (models.LocationDto, bool) {
+	next := location
+
+	switch strings.ToLower(cmd) {
+	case string(models.CommandForward):
+		next.Point = nextPoint(location, models.MoveTypeForward)
+	case string(models.CommandBackward):
+		next.Point = nextPoint(location, models.MoveTypeBackward)
+	case string(models.CommandLeft):
+		next.Direction = leftOf(gridDomain, location.Direction)
+		return next, true
+	case string(models.CommandRight):
+		next.Direction = rightOf(gridDomain, location.Direction)
+		return next, true
+	default:
+		return location, false
+	}
+
+	if !gridDomain.IsPointInGrid(next.Point) {
+		wrapped, isWrapped := gridDomain.WrapPoint(next.Point)
+		if !isWrapped {
+			return location, false
+		}
+		next.Point = wrapped
+	}
+
+	return next, true
+}
+
+type searchItem struct {
+	location models.LocationDto
+	cost     int
+	seq      int
+}
+
+// searchQueue is a heap of the locations to search ordered by cost, then by
+// discovery order
+type searchQueue []*searchItem
+
+func (q searchQueue) Len() int {
+	return len(q)
+}
+
+func (q searchQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+
+	return q[i].seq < q[j].seq
+}
+
+func (q searchQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *searchQueue) Push(x interface{}) {
+	*q = append(*q, x.(*searchItem))
+}
+
+func (q *searchQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return item
+}
@@ -1,97 +1,439 @@
 package domains
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/mars-rover-go/models"
 	"github.com/mars-rover-go/utils"
 )
 
+// RoverDomain is safe for concurrent use, the command batches are executed
+// one at a time
 type RoverDomain struct {
+	// batchMu serializes the command batches, mu guards the rover state
+	batchMu        sync.Mutex
+	mu             sync.Mutex
+	id             int
 	location       models.LocationDto
 	gridDomain     IGridDomain
 	obstacleDomain IObstacleDomain
+	eventStore     IEventStoreDomain
+	eventBus       IEventBusDomain
+	clock          IClockDomain
+	batch          int
+	sequence       int
+	// unpublished holds the events recorded but not published yet
+	unpublished []models.EventDto
 }
 
-func NewRoverDomain(startingLocation models.LocationDto, gridDomain IGridDomain, obstacleDomain IObstacleDomain) (IRoverDomain, error) {
+// BatchCancelledError reports a batch stopped by its context
+type BatchCancelledError struct {
+	// Location is the rover location after the last completed command
+	Location  models.LocationDto
+	Completed int
+	Err       error
+}
+
+func (e *BatchCancelledError) Error() string {
+	return fmt.Sprintf("batch cancelled after %d commands - last possible point: %s: %v", e.Completed, utils.LocationToString(e.Location), e.Err)
+}
+
+func (e *BatchCancelledError) Unwrap() error {
+	return e.Err
+}
+
+// ObstacleDetectedError reports a batch aborted by an obstacle
+type ObstacleDetectedError struct {
+	// Location is the last possible rover location
+	Location models.LocationDto
+	Point    models.PointDto
+}
+
+func (e *ObstacleDetectedError) Error() string {
+	return fmt.Sprintf("obstacle detected - last possible point: %s", utils.LocationToString(e.Location))
+}
+
+func NewRoverDomain(id int, startingLocation models.LocationDto, gridDomain IGridDomain, obstacleDomain IObstacleDomain, eventStore IEventStoreDomain, eventBus IEventBusDomain, clock IClockDomain) (IRoverDomain, error) {
 	isPointInGrid := gridDomain.IsPointInGrid(startingLocation.Point)
 	if !isPointInGrid {
 		return nil, fmt.Errorf("starting location is out the grid")
 	}
+	if !isDirection(gridDomain, startingLocation.Direction) {
+		return nil, fmt.Errorf("starting direction '%s' unknown", startingLocation.Direction)
+	}
+
+	rover := &RoverDomain{
+		id:             id,
+		gridDomain:     gridDomain,
+		obstacleDomain: obstacleDomain,
+		eventStore:     eventStore,
+		eventBus:       eventBus,
+		clock:          clock,
+	}
+	rover.record(models.EventDto{Type: models.EventTypeLanded, Location: startingLocation})
+	rover.publish()
 
-	return &RoverDomain{
-		startingLocation,
-		gridDomain,
-		obstacleDomain,
-	}, nil
+	return rover, nil
 }
 
 func (r *RoverDomain) ExecuteCommands(commands []string) (models.LocationDto, error) {
-	location := &r.location
+	return r.ExecuteTimedCommands(context.Background(), commands, models.CommandDurationDto{})
+}
+
+func (r *RoverDomain) ExecuteCommandsContext(ctx context.Context, commands []string) (models.LocationDto, error) {
+	return r.ExecuteTimedCommands(ctx, commands, models.CommandDurationDto{})
+}
 
+func (r *RoverDomain) ExecuteTimedCommands(ctx context.Context, commands []string, durations models.CommandDurationDto) (models.LocationDto, error) {
+	r.batchMu.Lock()
+	defer r.batchMu.Unlock()
+
+	return r.executeCommands(ctx, commands, durations)
+}
+
+func (r *RoverDomain) ExecutePlannedCommands(ctx context.Context, plan func(from models.LocationDto) ([]string, error), durations models.CommandDurationDto) (models.LocationDto, error) {
+	r.batchMu.Lock()
+	defer r.batchMu.Unlock()
+
+	location := r.Location()
+	commands, err := plan(location)
+	if err != nil {
+		return location, err
+	}
+
+	return r.executeCommands(ctx, commands, durations)
+}
+
+func (r *RoverDomain) executeCommands(ctx context.Context, commands []string, durations models.CommandDurationDto) (models.LocationDto, error) {
+	r.mu.Lock()
 	if len(commands) == 0 {
-		return *location, nil
-	}
-
-	for _, cmd := range commands {
-		var err error = nil
-		point := location.Point
-		direction := location.Direction
-
-		switch strings.ToLower(cmd) {
-		case string(models.CommandForward):
-			point, err = r.move(*location, models.MoveTypeForward)
-		case string(models.CommandBackward):
-			point, err = r.move(*location, models.MoveTypeBackward)
-		case string(models.CommandLeft):
-			direction = r.turnLeft(direction)
-		case string(models.CommandRight):
-			direction = r.turnRight(direction)
-		default:
-			return *location, fmt.Errorf("command '%s' unknown", cmd)
+		defer r.mu.Unlock()
+		return r.location, nil
+	}
+	r.batch++
+	r.mu.Unlock()
+
+	for i, cmd := range commands {
+		// The state is not locked while the command takes its time
+		waitErr := r.clock.Sleep(ctx, commandDuration(durations, cmd))
+
+		r.mu.Lock()
+		if waitErr != nil {
+			err := &BatchCancelledError{Location: r.location, Completed: i, Err: waitErr}
+			r.record(models.EventDto{Type: models.EventTypeBatchAborted, Command: cmd, Location: r.location, Reason: err.Error()})
+			location := r.location
+			r.mu.Unlock()
+			r.publish()
+
+			return location, err
 		}
 
+		err := r.executeCommand(cmd)
+		location := r.location
+		r.mu.Unlock()
+		r.publish()
+
 		if err != nil {
-			return *location, err
+			return location, err
 		}
+	}
+
+	r.mu.Lock()
+	r.record(models.EventDto{Type: models.EventTypeBatchCompleted, Location: r.location})
+	location := r.location
+	r.mu.Unlock()
+	r.publish()
+
+	return location, nil
+}
+
+// executeCommand executes one command, aborting the batch on error.
+// The state must be locked.
+func (r *RoverDomain) executeCommand(cmd string) error {
+	var err error = nil
+	location := r.location
+	eventType := models.EventTypeMoved
+
+	switch strings.ToLower(cmd) {
+	case string(models.CommandForward):
+		location.Point, eventType, err = r.moveEvent(models.MoveTypeForward)
+	case string(models.CommandBackward):
+		location.Point, eventType, err = r.moveEvent(models.MoveTypeBackward)
+	case string(models.CommandLeft):
+		location.Direction = r.turnLeft(location.Direction)
+		eventType = models.EventTypeTurned
+	case string(models.CommandRight):
+		location.Direction = r.turnRight(location.Direction)
+		eventType = models.EventTypeTurned
+	default:
+		err = fmt.Errorf("command '%s' unknown", cmd)
+	}
+
+	if err != nil {
+		r.record(models.EventDto{Type: models.EventTypeBatchAborted, Command: cmd, Location: r.location, Reason: err.Error()})
+		return err
+	}
+
+	if location != r.location {
+		r.record(models.EventDto{Type: eventType, Command: cmd, Location: location})
+	}
+
+	return nil
+}
+
+func (r *RoverDomain) Location() models.LocationDto {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.location
+}
+
+func (r *RoverDomain) Plan(commands []string) (models.PlanDto, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The planned rover does not share the locks, the state is copied
+
+	planStore := NewEventStoreDomain(DefaultSnapshotInterval)
+	planned := &RoverDomain{
+		id:             r.id,
+		location:       r.location,
+		gridDomain:     r.gridDomain,
+		obstacleDomain: r.obstacleDomain,
+		eventStore:     planStore,
+		clock:          r.clock,
+		batch:          r.batch,
+		sequence:       r.sequence,
+	}
+
+	location, err := planned.executeCommands(context.Background(), commands, models.CommandDurationDto{})
+
+	return models.PlanDto{
+		Location: location,
+		Events:   planStore.Events(r.id, r.sequence),
+	}, err
+}
+
+func (r *RoverDomain) Reset(location models.LocationDto) error {
+	r.batchMu.Lock()
+	defer r.batchMu.Unlock()
+	defer r.publish()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.gridDomain.IsPointInGrid(location.Point) {
+		return fmt.Errorf("location is out the grid")
+	}
+	if r.obstacleDomain.IsObstacle(location.Point) {
+		return fmt.Errorf("location is an obstacle")
+	}
+	if !isDirection(r.gridDomain, location.Direction) {
+		return fmt.Errorf("direction '%s' unknown", location.Direction)
+	}
+
+	r.batch++
+	r.record(models.EventDto{Type: models.EventTypeLanded, Location: location})
+
+	return nil
+}
 
-		location.Point = point
-		location.Direction = direction
+// moveEvent moves the rover, recording the obstacle encountered if any.
+// Returns the new point and the type of event the move produces.
+func (r *RoverDomain) moveEvent(moveType models.MoveType) (models.PointDto, models.EventType, error) {
+	eventType := models.EventTypeMoved
+	if !r.gridDomain.IsPointInGrid(nextPoint(r.location, moveType)) {
+		eventType = models.EventTypeEdgeWrapped
 	}
 
-	return *location, nil
+	point, err := r.move(r.location, moveType)
+	if obstacleErr, ok := err.(*ObstacleDetectedError); ok {
+		r.record(models.EventDto{
+			Type:     models.EventTypeObstacleEncountered,
+			Location: r.location,
+			Point:    obstacleErr.Point,
+		})
+	}
+
+	return point, eventType, err
 }
 
 func (r *RoverDomain) move(currentLocation models.LocationDto, moveType models.MoveType) (models.PointDto, error) {
-	point := currentLocation.Point
+	point := nextPoint(currentLocation, moveType)
 
-	switch currentLocation.Direction {
+	// Checks if the point is in the grid
+	if !r.gridDomain.IsPointInGrid(point) {
+		wrappedPoint, isWrapped := r.gridDomain.WrapPoint(point)
+		if !isWrapped {
+			return currentLocation.Point, nil
+		}
+		point = wrappedPoint
+	}
+
+	// Detectes obstacle
+	if isObstacleFrom(r.obstacleDomain, currentLocation.Point, point) {
+		return currentLocation.Point, &ObstacleDetectedError{Location: r.location, Point: point}
+	}
+	if corner, ok := cutsCorner(r.gridDomain, currentLocation, moveType, freeOf(r.obstacleDomain)); ok {
+		return currentLocation.Point, &ObstacleDetectedError{Location: r.location, Point: corner}
+	}
+
+	return point, nil
+}
+
+// record stamps the event with the rover identity, applies it to the rover
+// state, appends it to the event store and queues it for publish.
+// The state must be locked.
+func (r *RoverDomain) record(event models.EventDto) {
+	r.sequence++
+	event.RoverId = r.id
+	event.Sequence = r.sequence
+	event.Batch = r.batch
+
+	r.location = applyEvent(r.location, event)
+
+	if r.eventStore != nil {
+		r.eventStore.Append(event)
+	}
+
+	r.unpublished = append(r.unpublished, event)
+}
+
+// publish publishes the recorded events on the event bus. It is called once
+// the state is unlocked, so the subscribers blocking the bus can still read
+// the rover state, the batch lock keeping the events in order.
+func (r *RoverDomain) publish() {
+	r.mu.Lock()
+	events := r.unpublished
+	r.unpublished = nil
+	r.mu.Unlock()
+
+	if r.eventBus == nil {
+		return
+	}
+	for _, event := range events {
+		r.eventBus.Publish(event)
+	}
+}
+
+// commandDuration returns the simulated time the command takes
+func commandDuration(durations models.CommandDurationDto, cmd string) time.Duration {
+	milliseconds := 0
+
+	switch strings.ToLower(cmd) {
+	case string(models.CommandForward):
+		milliseconds = durations.ForwardMilliseconds
+	case string(models.CommandBackward):
+		milliseconds = durations.BackwardMilliseconds
+	case string(models.CommandLeft):
+		milliseconds = durations.LeftMilliseconds
+	case string(models.CommandRight):
+		milliseconds = durations.RightMilliseconds
+	}
+
+	return time.Duration(milliseconds) * time.Millisecond
+}
+
+// nextPoint returns the point one step away in the direction of the location,
+// without checking the grid
+func nextPoint(location models.LocationDto, moveType models.MoveType) models.PointDto {
+	dx, dy := directionStep(location.Direction)
+
+	return models.PointDto{
+		XPoint: location.Point.XPoint + dx*int(moveType),
+		YPoint: location.Point.YPoint + dy*int(moveType),
+	}
+}
+
+// directionStep returns the offset of a forward step in the direction
+func directionStep(direction models.Direction) (int, int) {
+	switch direction {
 	case models.DirectionNorth:
-		point.YPoint = point.YPoint + int(moveType)
+		return 0, 1
 	case models.DirectionSouth:
-		point.YPoint = point.YPoint - int(moveType)
+		return 0, -1
 	case models.DirectionEast:
-		point.XPoint = point.XPoint + int(moveType)
+		return 1, 0
 	case models.DirectionWest:
-		point.XPoint = point.XPoint - int(moveType)
+		return -1, 0
+	case models.DirectionNorthEast:
+		return 1, 1
+	case models.DirectionNorthWest:
+		return -1, 1
+	case models.DirectionSouthEast:
+		return 1, -1
+	case models.DirectionSouthWest:
+		return -1, -1
 	}
 
-	// Detectes obstacle
-	if r.obstacleDomain.IsObstacle(point) {
-		return currentLocation.Point, fmt.Errorf("obstacle detected - last possible point: %s", utils.LocationToString(r.location))
+	return 0, 0
+}
+
+// cutsCorner checks if the move is a diagonal step squeezing between the two
+// points at its corners, both blocked for the rover. Returns the first corner.
+func cutsCorner(gridDomain IGridDomain, location models.LocationDto, moveType models.MoveType, canEnter func(from models.PointDto, point models.PointDto) bool) (models.PointDto, bool) {
+	dx, dy := directionStep(location.Direction)
+	if dx == 0 || dy == 0 {
+		return models.PointDto{}, false
 	}
 
-	// Checks if the point is in the grid
-	isPointInGrid := r.gridDomain.IsPointInGrid(point)
-	if !isPointInGrid {
-		return currentLocation.Point, nil
+	corners := []models.PointDto{
+		{XPoint: location.Point.XPoint + dx*int(moveType), YPoint: location.Point.YPoint},
+		{XPoint: location.Point.XPoint, YPoint: location.Point.YPoint + dy*int(moveType)},
+	}
+	for i, corner := range corners {
+		if !gridDomain.IsPointInGrid(corner) {
+			corners[i], _ = gridDomain.WrapPoint(corner)
+		}
+		if canEnter(location.Point, corners[i]) {
+			return models.PointDto{}, false
+		}
 	}
 
-	return point, nil
+	return corners[0], true
+}
+
+// headings returns the directions the rover can head on the grid, clockwise
+// from north
+func headings(gridDomain IGridDomain) []models.Direction {
+	if gridDomain.HasDiagonals() {
+		return []models.Direction{
+			models.DirectionNorth, models.DirectionNorthEast, models.DirectionEast, models.DirectionSouthEast,
+			models.DirectionSouth, models.DirectionSouthWest, models.DirectionWest, models.DirectionNorthWest,
+		}
+	}
+
+	return []models.Direction{models.DirectionNorth, models.DirectionEast, models.DirectionSouth, models.DirectionWest}
+}
+
+func isDirection(gridDomain IGridDomain, direction models.Direction) bool {
+	for _, heading := range headings(gridDomain) {
+		if heading == direction {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (r *RoverDomain) turnLeft(currentDirection models.Direction) models.Direction {
+	return leftOf(r.gridDomain, currentDirection)
+}
+
+func (r *RoverDomain) turnRight(currentDirection models.Direction) models.Direction {
+	return rightOf(r.gridDomain, currentDirection)
+}
+
+// leftOf returns the direction after turning left, 45 degrees on the grids
+// with diagonals and 90 degrees otherwise
+func leftOf(gridDomain IGridDomain, currentDirection models.Direction) models.Direction {
+	if gridDomain.HasDiagonals() {
+		return turnHeading(gridDomain, currentDirection, -1)
+	}
+
 	var newDirection models.Direction
 
 	switch currentDirection {
@@ -108,7 +450,13 @@ func (r *RoverDomain) turnLeft(currentDirection models.Direction) models.Directi
 	return newDirection
 }
 
-func (r *RoverDomain) turnRight(currentDirection models.Direction) models.Direction {
+// rightOf returns the direction after turning right, 45 degrees on the grids
+// with diagonals and 90 degrees otherwise
+func rightOf(gridDomain IGridDomain, currentDirection models.Direction) models.Direction {
+	if gridDomain.HasDiagonals() {
+		return turnHeading(gridDomain, currentDirection, 1)
+	}
+
 	var newDirection models.Direction
 
 	switch currentDirection {
@@ -124,3 +472,16 @@ func (r *RoverDomain) turnRight(currentDirection models.Direction) models.Direct
 
 	return newDirection
 }
+
+// turnHeading returns the heading the given number of headings clockwise
+// from the direction, none for an unknown direction
+func turnHeading(gridDomain IGridDomain, currentDirection models.Direction, turns int) models.Direction {
+	headings := headings(gridDomain)
+	for i, heading := range headings {
+		if heading == currentDirection {
+			return headings[((i+turns)%len(headings)+len(headings))%len(headings)]
+		}
+	}
+
+	return ""
+}
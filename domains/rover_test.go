@@ -1,18 +1,30 @@
 package domains
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/mars-rover-go/models"
 )
 
 func TestNewRoverDomain(t *testing.T) {
 	type args struct {
+		id               int
 		startingLocation models.LocationDto
 		gridDomain       IGridDomain
 		obstacleDomain   IObstacleDomain
+		eventStore       IEventStoreDomain
+		eventBus         IEventBusDomain
+		clock            IClockDomain
 	}
+
+	clock := NewRealClockDomain()
+
 	tests := []struct {
 		name    string
 		args    args
@@ -24,7 +36,27 @@ func TestNewRoverDomain(t *testing.T) {
 			args: args{
 				startingLocation: models.LocationDto{Point: models.PointDto{XPoint: 11, YPoint: 5}, Direction: models.DirectionNorth},
 				gridDomain:       &GridDomain{models.GridDto{XPointMax: 10, YPointMax: 10}},
-				obstacleDomain:   &ObstacleDomain{[]models.ObstacleDto{}},
+				obstacleDomain:   &ObstacleDomain{obstacles: []models.ObstacleDto{}},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Starting direction is diagonal without diagonals",
+			args: args{
+				startingLocation: models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorthEast},
+				gridDomain:       &GridDomain{models.GridDto{XPointMax: 10, YPointMax: 10}},
+				obstacleDomain:   &ObstacleDomain{obstacles: []models.ObstacleDto{}},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Starting direction is unknown",
+			args: args{
+				startingLocation: models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: "X"},
+				gridDomain:       &GridDomain{models.GridDto{XPointMax: 10, YPointMax: 10}},
+				obstacleDomain:   &ObstacleDomain{obstacles: []models.ObstacleDto{}},
 			},
 			want:    nil,
 			wantErr: true,
@@ -32,21 +64,26 @@ func TestNewRoverDomain(t *testing.T) {
 		{
 			name: "New rover domain ok",
 			args: args{
+				id:               1,
 				startingLocation: models.LocationDto{Point: models.PointDto{XPoint: 3, YPoint: 5}, Direction: models.DirectionNorth},
 				gridDomain:       &GridDomain{models.GridDto{XPointMax: 10, YPointMax: 10}},
-				obstacleDomain:   &ObstacleDomain{[]models.ObstacleDto{}},
+				obstacleDomain:   &ObstacleDomain{obstacles: []models.ObstacleDto{}},
+				clock:            clock,
 			},
 			want: &RoverDomain{
-				models.LocationDto{Point: models.PointDto{XPoint: 3, YPoint: 5}, Direction: models.DirectionNorth},
-				&GridDomain{models.GridDto{XPointMax: 10, YPointMax: 10}},
-				&ObstacleDomain{[]models.ObstacleDto{}},
+				id:             1,
+				location:       models.LocationDto{Point: models.PointDto{XPoint: 3, YPoint: 5}, Direction: models.DirectionNorth},
+				gridDomain:     &GridDomain{models.GridDto{XPointMax: 10, YPointMax: 10}},
+				obstacleDomain: &ObstacleDomain{obstacles: []models.ObstacleDto{}},
+				clock:          clock,
+				sequence:       1,
 			},
 			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewRoverDomain(tt.args.startingLocation, tt.args.gridDomain, tt.args.obstacleDomain)
+			got, err := NewRoverDomain(tt.args.id, tt.args.startingLocation, tt.args.gridDomain, tt.args.obstacleDomain, tt.args.eventStore, tt.args.eventBus, tt.args.clock)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NewRoverDomain() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -126,6 +163,256 @@ func TestRoverDomain_ExecuteCommands(t *testing.T) {
 	}
 }
 
+func TestRoverDomain_ExecuteCommandsConcurrent(t *testing.T) {
+	rover := newRoverDomainMocked()
+	rover.eventStore = NewEventStoreDomain(DefaultSnapshotInterval)
+
+	// Every batch turns the rover on itself, so the rover ends facing north
+	// only if the batches are not interleaved
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 20; j++ {
+				if location, _ := rover.ExecuteCommands([]string{"r", "r", "r", "r"}); location.Direction != models.DirectionNorth {
+					t.Errorf("RoverDomain.ExecuteCommands() direction = %v, want %v", location.Direction, models.DirectionNorth)
+				}
+				_ = rover.Location()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(rover.eventStore.Events(0, 0)); got != 10*20*5 {
+		t.Errorf("RoverDomain events = %v, want %v", got, 10*20*5)
+	}
+}
+
+func TestRoverDomain_ExecuteCommandsContext(t *testing.T) {
+	type args struct {
+		ctx      context.Context
+		commands []string
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		args    args
+		want    models.LocationDto
+		wantErr error
+	}{
+		{
+			name: "Context not done",
+			args: args{
+				ctx:      context.Background(),
+				commands: []string{"f", "f"},
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 3}, Direction: models.DirectionNorth},
+			wantErr: nil,
+		},
+		{
+			name: "Context cancelled",
+			args: args{
+				ctx:      cancelled,
+				commands: []string{"f", "f"},
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorth},
+			wantErr: context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRoverDomainMocked()
+
+			got, err := r.ExecuteCommandsContext(tt.args.ctx, tt.args.commands)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RoverDomain.ExecuteCommandsContext() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RoverDomain.ExecuteCommandsContext() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoverDomain_ExecuteTimedCommands(t *testing.T) {
+	eventBus := NewEventBusDomain()
+	defer eventBus.Close()
+
+	rover := newRoverDomainMocked()
+	rover.eventBus = eventBus
+
+	// The operator aborts the traverse once the rover reaches (1,3)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	eventBus.Subscribe(SubscriberFunc(func(event models.EventDto) {
+		if event.Location.Point == (models.PointDto{XPoint: 1, YPoint: 3}) {
+			cancel()
+		}
+	}), 10, models.DeliveryPolicyBlock)
+
+	durations := models.CommandDurationDto{ForwardMilliseconds: 50}
+	got, err := rover.ExecuteTimedCommands(ctx, []string{"f", "f", "f", "f", "f"}, durations)
+
+	want := models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 3}, Direction: models.DirectionNorth}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RoverDomain.ExecuteTimedCommands() = %v, want %v", got, want)
+	}
+
+	var cancelledErr *BatchCancelledError
+	if !errors.As(err, &cancelledErr) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("RoverDomain.ExecuteTimedCommands() error = %v, want *BatchCancelledError", err)
+	}
+	if cancelledErr.Completed != 2 || cancelledErr.Location != want {
+		t.Errorf("RoverDomain.ExecuteTimedCommands() error = %+v, want 2 commands completed at %v", cancelledErr, want)
+	}
+}
+
+func TestRoverDomain_ExecuteCommandsBlockingSubscriber(t *testing.T) {
+	eventBus := NewEventBusDomain()
+	defer eventBus.Close()
+
+	rover := newRoverDomainMocked()
+	rover.eventBus = eventBus
+
+	// The subscriber reads the rover state while the rover waits on it
+	locations := []models.LocationDto{}
+	eventBus.Subscribe(SubscriberFunc(func(event models.EventDto) {
+		locations = append(locations, rover.Location())
+	}), 0, models.DeliveryPolicyBlock)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = rover.ExecuteCommands([]string{"f", "r", "f"})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RoverDomain.ExecuteCommands() deadlocked with a blocking subscriber")
+	}
+}
+
+func TestRoverDomain_ExecutePlannedCommands(t *testing.T) {
+	rover := newRoverDomainMocked()
+	target := models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 3}, Direction: models.DirectionNorth}
+
+	// Drives the rover facing north to the target from wherever it is
+	planToTarget := func(from models.LocationDto) ([]string, error) {
+		commands := []string{}
+		for y := from.Point.YPoint; y < target.Point.YPoint; y++ {
+			commands = append(commands, "f")
+		}
+		for y := from.Point.YPoint; y > target.Point.YPoint; y-- {
+			commands = append(commands, "b")
+		}
+		return commands, nil
+	}
+
+	// The other batches move the rover, never between a plan and its execution
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := rover.ExecuteCommands([]string{"f", "f"}); err != nil {
+				errs <- err
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			got, err := rover.ExecutePlannedCommands(context.Background(), planToTarget, models.CommandDurationDto{})
+			if err == nil && got != target {
+				err = fmt.Errorf("RoverDomain.ExecutePlannedCommands() = %v, want %v", got, target)
+			}
+			if err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	planErr := errors.New("no plan")
+	location := rover.Location()
+	got, err := rover.ExecutePlannedCommands(context.Background(), func(models.LocationDto) ([]string, error) {
+		return nil, planErr
+	}, models.CommandDurationDto{})
+	if !errors.Is(err, planErr) || got != location {
+		t.Errorf("RoverDomain.ExecutePlannedCommands() = %v, %v, want %v, %v", got, err, location, planErr)
+	}
+}
+
+func TestRoverDomain_Reset(t *testing.T) {
+	type args struct {
+		location models.LocationDto
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    models.LocationDto
+		wantErr bool
+	}{
+		{
+			name: "Location out the grid",
+			args: args{
+				location: models.LocationDto{Point: models.PointDto{XPoint: 11, YPoint: 5}, Direction: models.DirectionNorth},
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorth},
+			wantErr: true,
+		},
+		{
+			name: "Location is an obstacle",
+			args: args{
+				location: models.LocationDto{Point: models.PointDto{XPoint: 2, YPoint: 6}, Direction: models.DirectionNorth},
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorth},
+			wantErr: true,
+		},
+		{
+			name: "Direction unknown",
+			args: args{
+				location: models.LocationDto{Point: models.PointDto{XPoint: 3, YPoint: 3}, Direction: "X"},
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorth},
+			wantErr: true,
+		},
+		{
+			name: "Reset ok",
+			args: args{
+				location: models.LocationDto{Point: models.PointDto{XPoint: 3, YPoint: 3}, Direction: models.DirectionSouth},
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 3, YPoint: 3}, Direction: models.DirectionSouth},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRoverDomainMocked()
+
+			err := r.Reset(tt.args.location)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RoverDomain.Reset() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got := r.Location(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RoverDomain.Reset() location = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestRoverDomain_move(t *testing.T) {
 	type args struct {
 		currentLocation models.LocationDto
@@ -133,6 +420,8 @@ func TestRoverDomain_move(t *testing.T) {
 	}
 
 	roverDomain := newRoverDomainMocked()
+	wrappingRoverDomain := newRoverDomainMocked()
+	wrappingRoverDomain.gridDomain = &GridDomain{models.GridDto{XPointMax: 10, YPointMax: 10, Wrapping: true}}
 
 	tests := []struct {
 		name    string
@@ -219,6 +508,19 @@ func TestRoverDomain_move(t *testing.T) {
 			want:    models.PointDto{XPoint: 10, YPoint: 10},
 			wantErr: false,
 		},
+		{
+			name: "Move point out grid with wrapping",
+			r:    &wrappingRoverDomain,
+			args: args{
+				currentLocation: models.LocationDto{
+					Point:     models.PointDto{XPoint: 10, YPoint: 10},
+					Direction: models.DirectionNorth,
+				},
+				moveType: models.MoveTypeForward,
+			},
+			want:    models.PointDto{XPoint: 10, YPoint: 0},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -344,6 +646,87 @@ func TestRoverDomain_turnRight(t *testing.T) {
 	}
 }
 
+func TestRoverDomain_ExecuteCommandsDiagonals(t *testing.T) {
+	type args struct {
+		obstacles []models.ObstacleDto
+		commands  []string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    models.LocationDto
+		wantErr bool
+	}{
+		{
+			name: "Turn right 45 degrees",
+			args: args{
+				commands: []string{"r"},
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorthEast},
+			wantErr: false,
+		},
+		{
+			name: "Turn left all around",
+			args: args{
+				commands: []string{"l", "l", "l", "l", "l", "l", "l", "l"},
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorth},
+			wantErr: false,
+		},
+		{
+			name: "Move forward diagonally",
+			args: args{
+				commands: []string{"r", "r", "r", "f"},
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 2, YPoint: 0}, Direction: models.DirectionSouthEast},
+			wantErr: false,
+		},
+		{
+			name: "Move backward diagonally",
+			args: args{
+				commands: []string{"l", "b"},
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 2, YPoint: 0}, Direction: models.DirectionNorthWest},
+			wantErr: false,
+		},
+		{
+			name: "Move past one corner obstacle",
+			args: args{
+				obstacles: []models.ObstacleDto{{Point: models.PointDto{XPoint: 2, YPoint: 1}}},
+				commands:  []string{"r", "f"},
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 2, YPoint: 2}, Direction: models.DirectionNorthEast},
+			wantErr: false,
+		},
+		{
+			name: "Move cutting the corner between two obstacles",
+			args: args{
+				obstacles: []models.ObstacleDto{{Point: models.PointDto{XPoint: 2, YPoint: 1}}, {Point: models.PointDto{XPoint: 1, YPoint: 2}}},
+				commands:  []string{"r", "f"},
+			},
+			want:    models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorthEast},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRoverDomainMocked()
+			r.gridDomain = &GridDomain{models.GridDto{XPointMax: 10, YPointMax: 10, Diagonals: true}}
+			r.obstacleDomain = &ObstacleDomain{obstacles: tt.args.obstacles}
+
+			got, err := r.ExecuteCommands(tt.args.commands)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RoverDomain.ExecuteCommands() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RoverDomain.ExecuteCommands() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func newRoverDomainMocked() RoverDomain {
 	startingLocation := models.LocationDto{
 		Point: models.PointDto{
@@ -371,5 +754,6 @@ func newRoverDomainMocked() RoverDomain {
 		location:       startingLocation,
 		gridDomain:     &gridDomain,
 		obstacleDomain: &obstacleDomain,
+		clock:          NewRealClockDomain(),
 	}
 }
@@ -0,0 +1,113 @@
+package domains
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mars-rover-go/models"
+)
+
+// SimulationDomain drives the rover on a simulation clock: each command takes
+// its duration of simulated time, and the other subsystems sharing the clock
+// (communications, obstacles) are scheduled on the same timeline.
+// The rover must sleep on the same simulation clock.
+type SimulationDomain struct {
+	rover     IRoverDomain
+	clock     *SimulationClockDomain
+	durations models.CommandDurationDto
+
+	mu      sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	queue   []simulatedBatch
+	running bool
+	batch   int
+	results []models.SimulationResultDto
+}
+
+type simulatedBatch struct {
+	ctx    context.Context
+	result models.SimulationResultDto
+}
+
+func NewSimulationDomain(rover IRoverDomain, clock *SimulationClockDomain, durations models.CommandDurationDto) ISimulationDomain {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &SimulationDomain{
+		rover:     rover,
+		clock:     clock,
+		durations: durations,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+func (s *SimulationDomain) Submit(commands []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.batch++
+	s.queue = append(s.queue, simulatedBatch{
+		ctx: s.ctx,
+		result: models.SimulationResultDto{
+			Batch:                 s.batch,
+			Commands:              commands,
+			SubmittedMilliseconds: s.clock.Now().Milliseconds(),
+		},
+	})
+
+	// A single task runs the batches so they execute in submission order
+	if !s.running {
+		s.running = true
+		s.clock.Go(s.run)
+	}
+}
+
+func (s *SimulationDomain) Abort() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cancel()
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+}
+
+func (s *SimulationDomain) RunUntil(t time.Duration) {
+	s.clock.RunUntil(t)
+}
+
+func (s *SimulationDomain) Run() time.Duration {
+	return s.clock.Run()
+}
+
+func (s *SimulationDomain) Results() []models.SimulationResultDto {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.SimulationResultDto{}, s.results...)
+}
+
+func (s *SimulationDomain) run() {
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.running = false
+			s.mu.Unlock()
+			return
+		}
+		batch := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		location, err := s.rover.ExecuteTimedCommands(batch.ctx, batch.result.Commands, s.durations)
+		batch.result.CompletedMilliseconds = s.clock.Now().Milliseconds()
+		batch.result.Location = location
+		if err != nil {
+			batch.result.Error = err.Error()
+		}
+
+		s.mu.Lock()
+		s.results = append(s.results, batch.result)
+		s.mu.Unlock()
+	}
+}
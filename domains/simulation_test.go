@@ -0,0 +1,99 @@
+package domains
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestSimulationDomain_Run(t *testing.T) {
+	durations := models.CommandDurationDto{ForwardMilliseconds: 1000, BackwardMilliseconds: 1000, LeftMilliseconds: 500, RightMilliseconds: 500}
+
+	tests := []struct {
+		name    string
+		batches [][]string
+		abortAt time.Duration
+		after   [][]string
+		want    []models.SimulationResultDto
+		wantEnd time.Duration
+	}{
+		{
+			name:    "Batches executed in submission order",
+			batches: [][]string{{"f", "f"}, {"r", "T"}},
+			want: []models.SimulationResultDto{
+				{
+					Batch:                 1,
+					Commands:              []string{"f", "f"},
+					CompletedMilliseconds: 2000,
+					Location:              models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 3}, Direction: models.DirectionNorth},
+				},
+				{
+					Batch:                 2,
+					Commands:              []string{"r", "T"},
+					CompletedMilliseconds: 2500,
+					Location:              models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 3}, Direction: models.DirectionEast},
+					Error:                 "command 'T' unknown",
+				},
+			},
+			wantEnd: 2500 * time.Millisecond,
+		},
+		{
+			name:    "Batches aborted",
+			batches: [][]string{{"f", "f", "f", "f"}, {"r"}},
+			abortAt: 2500 * time.Millisecond,
+			after:   [][]string{{"l"}},
+			want: []models.SimulationResultDto{
+				{
+					Batch:                 1,
+					Commands:              []string{"f", "f", "f", "f"},
+					CompletedMilliseconds: 3000,
+					Location:              models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 3}, Direction: models.DirectionNorth},
+					Error:                 "batch cancelled after 2 commands - last possible point: (1,3) N: context canceled",
+				},
+				{
+					Batch:                 2,
+					Commands:              []string{"r"},
+					CompletedMilliseconds: 3000,
+					Location:              models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 3}, Direction: models.DirectionNorth},
+					Error:                 "batch cancelled after 0 commands - last possible point: (1,3) N: context canceled",
+				},
+				{
+					Batch:                 3,
+					Commands:              []string{"l"},
+					SubmittedMilliseconds: 2500,
+					CompletedMilliseconds: 3500,
+					Location:              models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 3}, Direction: models.DirectionWest},
+				},
+			},
+			wantEnd: 3500 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewSimulationClockDomain(time.Millisecond)
+			rover := newRoverDomainMocked()
+			rover.clock = clock
+
+			s := NewSimulationDomain(&rover, clock, durations)
+			for _, commands := range tt.batches {
+				s.Submit(commands)
+			}
+			if tt.abortAt > 0 {
+				s.RunUntil(tt.abortAt)
+				s.Abort()
+			}
+			for _, commands := range tt.after {
+				s.Submit(commands)
+			}
+
+			if end := s.Run(); end != tt.wantEnd {
+				t.Errorf("SimulationDomain.Run() = %v, want %v", end, tt.wantEnd)
+			}
+			if got := s.Results(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SimulationDomain.Results() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
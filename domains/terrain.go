@@ -0,0 +1,76 @@
+package domains
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/mars-rover-go/models"
+)
+
+// TerrainDomain finds the obstacles from the slopes of a heightmap: the rover
+// cannot climb a slope steeper than the maximum, and drives down any slope
+type TerrainDomain struct {
+	gridDomain IGridDomain
+	heightmap  models.HeightmapDto
+	cellSize   float64
+	maxSlope   float64
+}
+
+// NewTerrainDomain returns the terrain of the grid, the heightmap having one
+// elevation per grid point
+func NewTerrainDomain(grid models.GridDto, heightmap models.HeightmapDto, terrain models.TerrainDto) (IDirectionalObstacleDomain, error) {
+	if len(heightmap.Elevations) != grid.YPointMax+1 {
+		return nil, fmt.Errorf("heightmap has %d rows, want %d for the grid", len(heightmap.Elevations), grid.YPointMax+1)
+	}
+	for y, elevations := range heightmap.Elevations {
+		if len(elevations) != grid.XPointMax+1 {
+			return nil, fmt.Errorf("heightmap row %d has %d points, want %d for the grid", y, len(elevations), grid.XPointMax+1)
+		}
+	}
+
+	cellSize := terrain.CellSizeMeters
+	if cellSize <= 0 {
+		cellSize = 1
+	}
+
+	return &TerrainDomain{
+		gridDomain: NewGridDomain(grid),
+		heightmap:  heightmap,
+		cellSize:   cellSize,
+		maxSlope:   terrain.MaxSlope,
+	}, nil
+}
+
+// IsObstacle checks if the point is too steep to climb from every neighbour
+func (t *TerrainDomain) IsObstacle(point models.PointDto) bool {
+	if !t.gridDomain.IsPointInGrid(point) {
+		return false
+	}
+
+	for _, direction := range headings(t.gridDomain) {
+		neighbour, ok := nextLocation(t.gridDomain, models.LocationDto{Point: point, Direction: direction}, string(models.CommandForward))
+		if ok && !t.IsObstacleFrom(neighbour.Point, point) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t *TerrainDomain) IsObstacleFrom(from models.PointDto, point models.PointDto) bool {
+	if !t.gridDomain.IsPointInGrid(from) || !t.gridDomain.IsPointInGrid(point) || from == point {
+		return false
+	}
+
+	rise := t.elevation(point) - t.elevation(from)
+	distance := t.cellSize
+	if from.XPoint != point.XPoint && from.YPoint != point.YPoint {
+		distance *= math.Sqrt2
+	}
+
+	return rise/distance > t.maxSlope
+}
+
+func (t *TerrainDomain) elevation(point models.PointDto) float64 {
+	return t.heightmap.Elevations[point.YPoint][point.XPoint]
+}
@@ -0,0 +1,101 @@
+package domains
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestTerrainDomain_IsObstacleFrom(t *testing.T) {
+	// A peak in the middle of a ramp going up to the east
+	heightmap := models.HeightmapDto{Elevations: [][]float64{
+		{0, 2, 4},
+		{0, 9, 4},
+		{0, 2, 4},
+	}}
+	terrain, err := NewTerrainDomain(models.GridDto{XPointMax: 2, YPointMax: 2}, heightmap, models.TerrainDto{CellSizeMeters: 2, MaxSlope: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		from  models.PointDto
+		point models.PointDto
+		want  bool
+	}{
+		{
+			name:  "Slope climbed",
+			from:  models.PointDto{XPoint: 0, YPoint: 0},
+			point: models.PointDto{XPoint: 1, YPoint: 0},
+			want:  false,
+		},
+		{
+			name:  "Slope too steep to climb",
+			from:  models.PointDto{XPoint: 1, YPoint: 0},
+			point: models.PointDto{XPoint: 1, YPoint: 1},
+			want:  true,
+		},
+		{
+			name:  "Steep slope driven down",
+			from:  models.PointDto{XPoint: 1, YPoint: 1},
+			point: models.PointDto{XPoint: 1, YPoint: 0},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := terrain.IsObstacleFrom(tt.from, tt.point); got != tt.want {
+				t.Errorf("TerrainDomain.IsObstacleFrom() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if !terrain.IsObstacle(models.PointDto{XPoint: 1, YPoint: 1}) {
+		t.Errorf("TerrainDomain.IsObstacle() = false, want true for the peak")
+	}
+	if terrain.IsObstacle(models.PointDto{XPoint: 2, YPoint: 1}) {
+		t.Errorf("TerrainDomain.IsObstacle() = true, want false for the ramp")
+	}
+}
+
+func TestNewTerrainDomain(t *testing.T) {
+	heightmap := models.HeightmapDto{Elevations: [][]float64{{0, 1}, {0, 1}}}
+
+	if _, err := NewTerrainDomain(models.GridDto{XPointMax: 2, YPointMax: 1}, heightmap, models.TerrainDto{MaxSlope: 1}); err == nil {
+		t.Errorf("NewTerrainDomain() error = nil, want heightmap size error")
+	}
+	if _, err := NewTerrainDomain(models.GridDto{XPointMax: 1, YPointMax: 1}, heightmap, models.TerrainDto{MaxSlope: 1}); err != nil {
+		t.Errorf("NewTerrainDomain() error = %v", err)
+	}
+}
+
+func TestRoverDomain_ExecuteCommandsOnTerrain(t *testing.T) {
+	heightmap := models.HeightmapDto{Elevations: [][]float64{
+		{0, 0, 0},
+		{0, 5, 0},
+		{0, 0, 0},
+	}}
+	grid := models.GridDto{XPointMax: 2, YPointMax: 2}
+	terrain, err := NewTerrainDomain(grid, heightmap, models.TerrainDto{MaxSlope: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rover := newRoverDomainMocked()
+	rover.gridDomain = NewGridDomain(grid)
+	rover.obstacleDomain = terrain
+	rover.location = models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 1}, Direction: models.DirectionNorth}
+
+	// Down the peak, then back up
+	location, err := rover.ExecuteCommands([]string{"f", "b"})
+
+	var obstacleErr *ObstacleDetectedError
+	if !errors.As(err, &obstacleErr) {
+		t.Fatalf("RoverDomain.ExecuteCommands() error = %v, want obstacle detected", err)
+	}
+	if want := (models.PointDto{XPoint: 1, YPoint: 2}); location.Point != want {
+		t.Errorf("RoverDomain.ExecuteCommands() = %v, want %v", location.Point, want)
+	}
+}
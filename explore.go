@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/mars-rover-go/domains"
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/utils"
+)
+
+// exploreGrid lets the rover explore the grid on its own and prints the
+// coverage after each batch, e.g. "explore -strategy random -coverage 0.8"
+func exploreGrid(args []string, components *components, grid models.GridDto) error {
+	flags := flag.NewFlagSet("explore", flag.ContinueOnError)
+	strategy := flags.String("strategy", string(models.ExplorationStrategyNearest), "Exploration strategy: nearest or random")
+	coverage := flags.Float64("coverage", 1, "Coverage target, from 0 to 1")
+	budget := flags.Int("budget", 0, "Maximum number of commands, unlimited when 0")
+	seed := flags.Int64("seed", 1, "Seed of the random strategy")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	explorer := domains.NewExplorerDomain(components.rover, grid, components.knownMap, *seed)
+	steps, err := explorer.Explore(models.ExplorationStrategy(*strategy), *coverage, *budget)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("step\tcommands\tlocation\tused\texplored\tcoverage")
+	for _, step := range steps {
+		fmt.Printf("%d\t%s\t%s\t%d\t%d\t%.1f%%\n",
+			step.Step,
+			strings.Join(step.Commands, ""),
+			utils.LocationToString(step.Location),
+			step.CommandsUsed,
+			step.ExploredPoints,
+			step.Coverage*100)
+		if step.Error != "" {
+			fmt.Printf("\tABORTED - %s\n", step.Error)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/mars-rover-go/domains"
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/utils"
+)
+
+// generateMap writes a configuration with a generated grid and obstacles,
+// the other settings are the current ones but the security key, e.g.
+// "generate -seed 7 -density 0.2 -clusters 3 -out map.json"
+func generateMap(args []string, config models.ConfigurationDto, start models.PointDto) error {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	seed := flags.Int64("seed", 1, "Seed of the map, the same seed gives the same map")
+	xPointMax := flags.Int("x", config.Grid.XPointMax, "Grid XPointMax")
+	yPointMax := flags.Int("y", config.Grid.YPointMax, "Grid YPointMax")
+	wrapping := flags.Bool("wrapping", config.Grid.Wrapping, "Grid wrapping")
+	diagonals := flags.Bool("diagonals", config.Grid.Diagonals, "Grid diagonals, the rover heading in eight directions")
+	density := flags.Float64("density", 0.2, "Ratio of the grid points that are obstacles")
+	clusters := flags.Int("clusters", 0, "Number of boulder fields, the obstacles are scattered when 0")
+	radius := flags.Int("radius", 2, "Spread of the boulder fields")
+	goalX := flags.Int("gx", -1, "Goal X point connected to the start, the grid corner opposite the origin when not set")
+	goalY := flags.Int("gy", -1, "Goal Y point connected to the start, the grid corner opposite the origin when not set")
+	out := flags.String("out", "map.json", "Configuration file written")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	goal := models.PointDto{XPoint: *goalX, YPoint: *goalY}
+	if *goalX < 0 {
+		goal.XPoint = *xPointMax
+	}
+	if *goalY < 0 {
+		goal.YPoint = *yPointMax
+	}
+
+	generated, err := domains.NewMapGeneratorDomain().Generate(models.MapGenerationDto{
+		Seed:          *seed,
+		Grid:          models.GridDto{XPointMax: *xPointMax, YPointMax: *yPointMax, Wrapping: *wrapping, Diagonals: *diagonals},
+		Density:       *density,
+		Clusters:      *clusters,
+		ClusterRadius: *radius,
+		Start:         start,
+		Goal:          goal,
+	})
+	if err != nil {
+		return err
+	}
+
+	config.Grid = generated.Grid
+	config.Obstacle = generated.Obstacle
+	// The generated maps are shared, they do not carry the security key
+	config.Security.Key = ""
+	if err := utils.SaveConfiguration(*out, config); err != nil {
+		return err
+	}
+
+	fmt.Printf("Map written to %s: %d obstacles, %s connected to %s\n", *out, len(config.Obstacle), utils.PointToString(start), utils.PointToString(goal))
+
+	return nil
+}
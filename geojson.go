@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/utils"
+)
+
+// geoJson imports the obstacles of GeoJSON polygons or exports the rover
+// trajectory as GeoJSON LineStrings, the grid points being mapped to
+// latitude and longitude with the configured geo reference, e.g.
+// "geojson import -save craters.geojson" or "geojson export -out run.geojson ffrff"
+func geoJson(args []string, components *components, config models.ConfigurationDto) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: geojson import [-save] <file> | geojson export [-out <file>] [commands]")
+	}
+
+	switch args[0] {
+	case "import":
+		return importGeoJson(args[1:], components, config)
+	case "export":
+		return exportGeoJson(args[1:], components, config)
+	default:
+		return fmt.Errorf("geojson subcommand '%s' unknown", args[0])
+	}
+}
+
+func importGeoJson(args []string, components *components, config models.ConfigurationDto) error {
+	flags := flag.NewFlagSet("geojson import", flag.ContinueOnError)
+	save := flags.Bool("save", false, "Saves the obstacles to the ASCII map or the configuration")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: geojson import [-save] <file>")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	obstacles, err := utils.ImportGeoJsonObstacles(file, config.Geo, config.Grid)
+	if err != nil {
+		return err
+	}
+	all, err := components.obstacles.Add(obstacles)
+	if err != nil {
+		return err
+	}
+
+	points := make([]models.PointDto, 0, len(obstacles))
+	for _, obstacle := range obstacles {
+		points = append(points, obstacle.Point)
+	}
+	fmt.Printf("Obstacles imported: %d, total: %d\n", len(obstacles), len(all))
+	if len(points) > 0 {
+		fmt.Printf("Imported points: %s\n", pointsToString(points))
+	}
+
+	if *save {
+		return components.obstacles.Save()
+	}
+
+	return nil
+}
+
+func exportGeoJson(args []string, components *components, config models.ConfigurationDto) error {
+	flags := flag.NewFlagSet("geojson export", flag.ContinueOnError)
+	out := flags.String("out", "", "GeoJSON file written, the standard output when not set")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if config.Geo.CellSizeMeters <= 0 {
+		return fmt.Errorf("geo reference cell size is not set")
+	}
+
+	// The commands batches run before the export, the trajectory of a
+	// batch stopping on an obstacle is still exported
+	for _, batch := range flags.Args() {
+		if _, err := components.rover.ExecuteCommands(strings.Split(batch, "")); err != nil {
+			fmt.Fprintf(os.Stderr, "WARNING - %v\n", err)
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	return utils.WriteGeoJsonTrajectory(w, config.Geo, components.eventStore.Events(roverId, 0))
+}
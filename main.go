@@ -1,27 +1,52 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"strings"
 
 	"github.com/eiannone/keyboard"
+	"github.com/mars-rover-go/api"
 	"github.com/mars-rover-go/domains"
 	"github.com/mars-rover-go/models"
 	"github.com/mars-rover-go/utils"
 )
 
+const roverId = 1
+const configPath = "./config.json"
+const eventLogBufferSize = 100
+
 var startXPoint int
 var startYPoint int
 var startDirection string
+var logEvents bool
+var mapPath string
 
 func init() {
 	flag.IntVar(&startXPoint, "sx", 0, "Starting X point")
 	flag.IntVar(&startYPoint, "sy", 0, "Starting Y point")
 	flag.StringVar(&startDirection, "d", string(models.DirectionNorth), "Starting direction")
+	flag.BoolVar(&logEvents, "events", false, "Log the rover events")
+	flag.StringVar(&mapPath, "map", "", "ASCII map file setting the grid, obstacles and starting location")
+}
+
+type components struct {
+	rover      domains.IRoverDomain
+	eventStore domains.IEventStoreDomain
+	eventBus   domains.IEventBusDomain
+	comms      domains.ICommsDomain
+	envelope   domains.IEnvelopeDomain
+	obstacles  *api.ObstacleController
+	// knownMap is the map built by the rover sensor
+	knownMap domains.IKnownMapDomain
+	coverage domains.ICoveragePlannerDomain
+	mission  domains.IMissionPlannerDomain
+	// analysis analyzes the ground truth obstacles the rover drives on
+	analysis domains.IMapAnalysisDomain
+	// navigator drives the rover home or to the safe zones
+	navigator domains.INavigatorDomain
 }
 
 func main() {
@@ -32,22 +57,74 @@ func main() {
 		Direction: models.Direction(strings.ToUpper(startDirection)),
 	}
 
-	config, err := getConfiguration()
+	config, err := utils.LoadConfiguration(configPath)
 	if err != nil {
 		fmt.Printf("ERROR - %+v\n", err)
 		return
 	}
+	if mapPath != "" {
+		if err := loadAsciiMap(mapPath, config, &startingLocation); err != nil {
+			fmt.Printf("ERROR - %+v\n", err)
+			return
+		}
+	}
 
-	roverDomain, err := initComponents(startingLocation, config)
+	// The simulation runs on a virtual clock, every other mode on the wall clock
+	clock := domains.NewRealClockDomain()
+	var simulationClock *domains.SimulationClockDomain
+	if flag.Arg(0) == "simulate" {
+		simulationClock = domains.NewSimulationClockDomain(simulationTick)
+		clock = simulationClock
+	}
+
+	components, err := initComponents(startingLocation, config, clock)
 	if err != nil {
 		fmt.Printf("ERROR - %+v\n", err)
 		return
 	}
+	defer components.eventBus.Close()
+
+	if logEvents {
+		components.eventBus.Subscribe(domains.SubscriberFunc(func(event models.EventDto) {
+			fmt.Printf("\n\tEVENT - %s", utils.EventToString(event))
+		}), eventLogBufferSize, models.DeliveryPolicyDrop)
+	}
+
+	switch flag.Arg(0) {
+	case "":
+		startExecution(*config, startingLocation, components)
+	case "serve":
+		err = startServer(flag.Args()[1:], components)
+	case "tcp":
+		err = startTcpServer(flag.Args()[1:], components)
+	case "sign":
+		err = signCommands(flag.Args()[1:], components)
+	case "explore":
+		err = exploreGrid(flag.Args()[1:], components, config.Grid)
+	case "survey":
+		err = surveyRegion(flag.Args()[1:], components)
+	case "mission":
+		err = planMission(flag.Args()[1:], components)
+	case "analyze":
+		err = analyzeMap(flag.Args()[1:], components, config.Grid, startingLocation.Point)
+	case "generate":
+		err = generateMap(flag.Args()[1:], *config, startingLocation.Point)
+	case "map":
+		err = writeAsciiMap(flag.Args()[1:], *config, startingLocation)
+	case "geojson":
+		err = geoJson(flag.Args()[1:], components, *config)
+	case "simulate":
+		err = simulateCommands(flag.Args()[1:], components, simulationClock, config.CommandDuration)
+	default:
+		err = fmt.Errorf("subcommand '%s' unknown", flag.Arg(0))
+	}
 
-	startExecution(*config, startingLocation, roverDomain)
+	if err != nil {
+		fmt.Printf("ERROR - %+v\n", err)
+	}
 }
 
-func startExecution(config models.ConfigurationDto, startingLocation models.LocationDto, roverDomain domains.IRoverDomain) {
+func startExecution(config models.ConfigurationDto, startingLocation models.LocationDto, components *components) {
 	keysEvents, err := keyboard.GetKeys(10)
 	if err != nil {
 		panic(err)
@@ -61,48 +138,157 @@ func startExecution(config models.ConfigurationDto, startingLocation models.Loca
 	fmt.Println("--------------------------")
 
 	fmt.Println("- Commands available:\n\t. f: forward\n\t. b: backward\n\t. l: left\n\t. r: right")
+	fmt.Println("- Press x to abort the running batch")
+	fmt.Println("- Press h to return to the start location, z to retreat to the nearest safe zone")
+	fmt.Println("- Press o to edit the obstacles:\n\t. add <x> <y>...\n\t. remove <x> <y>...\n\t. import <file>\n\t. save\n\t. list")
 	fmt.Print("- Press ESC to quit\n\n")
 
 	fmt.Println("Grid")
 	fmt.Printf("\tXPointMax: %d, YPointMax: %d\n\n", config.Grid.XPointMax, config.Grid.XPointMax)
 
 	fmt.Println("Obstacles")
-	if len(config.Obstacle) == 0 {
-		fmt.Println("\tNo obstacles")
-	}
-	for _, o := range config.Obstacle {
-		fmt.Printf("\tXPoint: %d, YPoint: %d \n", o.Point.XPoint, o.Point.YPoint)
+	if config.Sensor.Range > 0 {
+		fmt.Printf("\tHidden, discovered by the sensor: range %d, field of view %d degrees\n", config.Sensor.Range, config.Sensor.FieldOfViewDegrees)
+		fmt.Println("\tPress m to print the known map")
+	} else {
+		if len(config.Obstacle) == 0 {
+			fmt.Println("\tNo obstacles")
+		}
+		for _, o := range config.Obstacle {
+			fmt.Printf("\tXPoint: %d, YPoint: %d \n", o.Point.XPoint, o.Point.YPoint)
+		}
 	}
 
 	fmt.Printf("\nStart location: %s\n\n", utils.LocationToString(startingLocation))
 
+	if components.comms != nil {
+		fmt.Printf("Light time: %dms\n\n", config.Comms.LightTimeMilliseconds)
+		defer components.comms.Close()
+		go printDownlinks(components.comms)
+	}
+
 	commands := []string{}
 	fmt.Print("Write commands: ")
 
+	// obstacleCommand is the obstacle command being written after pressing o
+	var obstacleCommand []rune
+
+	// A batch runs in the background so the operator can abort it
+	var abortBatch context.CancelFunc
+	batchResults := make(chan batchResult, 1)
+
 	for {
-		event := <-keysEvents
+		var event keyboard.KeyEvent
+		select {
+		case result := <-batchResults:
+			abortBatch()
+			abortBatch = nil
+			if result.err != nil {
+				fmt.Printf("\n\tERROR - %+v\n", result.err)
+			}
+			fmt.Printf("\n\tLocation: %s\n\n", utils.LocationToString(result.location))
+			fmt.Print("Write commands: ")
+			continue
+		case event = <-keysEvents:
+		}
+
 		if event.Err != nil {
 			panic(event.Err)
 		}
 
 		if event.Key == keyboard.KeyEsc {
+			if abortBatch != nil {
+				abortBatch()
+			}
 			break
 		}
-		if event.Key == keyboard.KeyEnter {
-			location, err := roverDomain.ExecuteCommands(commands)
-			if err != nil {
-				fmt.Printf("\n\tERROR - %+v\n", err)
+		if obstacleCommand != nil {
+			switch event.Key {
+			case keyboard.KeyEnter:
+				executeObstacleCommand(components.obstacles, string(obstacleCommand))
+				obstacleCommand = nil
+				fmt.Print("Write commands: ")
+			case keyboard.KeyBackspace, keyboard.KeyBackspace2:
+				if len(obstacleCommand) > 0 {
+					obstacleCommand = obstacleCommand[:len(obstacleCommand)-1]
+					fmt.Print("\b \b")
+				}
+			case keyboard.KeySpace:
+				obstacleCommand = append(obstacleCommand, ' ')
+				fmt.Print(" ")
+			default:
+				if event.Rune != 0 {
+					obstacleCommand = append(obstacleCommand, event.Rune)
+					fmt.Print(string(event.Rune))
+				}
 			}
+			continue
+		}
+		if event.Key == keyboard.KeyEnter && components.comms != nil {
+			batchId := components.comms.Uplink(commands)
 
 			commands = []string{}
-			fmt.Printf("\n\tLocation: %s\n\n", utils.LocationToString(location))
+			fmt.Printf("\n\tBatch #%d sent\n\n", batchId)
 			fmt.Print("Write commands: ")
 			continue
 		}
+		if event.Key == keyboard.KeyEnter {
+			if abortBatch != nil {
+				fmt.Print("\n\tBatch running, press x to abort it\n")
+				continue
+			}
+
+			var ctx context.Context
+			ctx, abortBatch = context.WithCancel(context.Background())
+			go func(commands []string) {
+				location, err := components.navigator.ExecuteTimedCommands(ctx, commands, config.CommandDuration)
+				batchResults <- batchResult{location, err}
+			}(commands)
+
+			commands = []string{}
+			continue
+		}
 
 		switch string(event.Rune) {
 		case "f", "b", "l", "r":
 			commands = append(commands, string(event.Rune))
+		case "x":
+			if abortBatch != nil {
+				abortBatch()
+				fmt.Print("\n\tAborting batch\n")
+			}
+			continue
+		case "h", "z":
+			if abortBatch != nil {
+				fmt.Print("\n\tBatch running, press x to abort it\n")
+				continue
+			}
+
+			navigate := components.navigator.ReturnHome
+			if event.Rune == 'z' {
+				navigate = components.navigator.Retreat
+				fmt.Print("\n\tRetreating to the nearest safe zone\n")
+			} else {
+				fmt.Print("\n\tReturning to the start location\n")
+			}
+
+			var ctx context.Context
+			ctx, abortBatch = context.WithCancel(context.Background())
+			go func() {
+				location, err := navigate(ctx, config.CommandDuration)
+				batchResults <- batchResult{location, err}
+			}()
+			continue
+		case "m":
+			if config.Sensor.Range > 0 {
+				printKnownMap(config.Grid, components.knownMap, components.rover.Location())
+				fmt.Print("Write commands: ")
+			}
+			continue
+		case "o":
+			obstacleCommand = []rune{}
+			fmt.Print("\nObstacle command: ")
+			continue
 		default:
 			continue
 		}
@@ -111,29 +297,134 @@ func startExecution(config models.ConfigurationDto, startingLocation models.Loca
 	}
 }
 
-func getConfiguration() (*models.ConfigurationDto, error) {
-	var config models.ConfigurationDto
+type batchResult struct {
+	location models.LocationDto
+	err      error
+}
 
-	configFilePath, err := os.Open("./config.json")
-	if err != nil {
-		return nil, err
+// executeObstacleCommand edits the obstacles, importing them from a JSON file
+// with "import <file>"
+func executeObstacleCommand(obstacles *api.ObstacleController, command string) {
+	var edited []models.ObstacleDto
+	var err error
+
+	if fields := strings.Fields(command); len(fields) == 2 && strings.EqualFold(fields[0], "import") {
+		var file *os.File
+		if file, err = os.Open(fields[1]); err != nil {
+			edited = obstacles.Obstacles()
+		} else {
+			edited, err = obstacles.Import(file)
+			file.Close()
+		}
+	} else {
+		edited, err = obstacles.ExecuteCommand(command)
 	}
-	defer configFilePath.Close()
-	configValue, _ := ioutil.ReadAll(configFilePath)
 
-	err = json.Unmarshal(configValue, &config)
 	if err != nil {
-		return nil, err
+		fmt.Printf("\n\tERROR - %+v\n", err)
 	}
+	fmt.Printf("\n\tObstacles: %s\n\n", utils.ObstaclesToString(edited))
+}
 
-	return &config, nil
+// printKnownMap prints the map known by the rover, north up: '?' unexplored,
+// '.' free, '#' obstacle and the rover as its direction glyph
+func printKnownMap(grid models.GridDto, knownMap domains.IKnownMapDomain, location models.LocationDto) {
+	knownMap.Sync()
+
+	fmt.Print("\n\n")
+	for y := grid.YPointMax; y >= 0; y-- {
+		fmt.Print("\t")
+		for x := 0; x <= grid.XPointMax; x++ {
+			point := models.PointDto{XPoint: x, YPoint: y}
+			switch {
+			case point == location.Point:
+				fmt.Printf("%c", utils.DirectionGlyph(location.Direction))
+			case !knownMap.IsExplored(point):
+				fmt.Print("?")
+			case knownMap.IsObstacle(point):
+				fmt.Print("#")
+			default:
+				fmt.Print(".")
+			}
+		}
+		fmt.Println()
+	}
+	fmt.Printf("\n\tExplored: %d points, known obstacles: %s\n\n", knownMap.ExploredPoints(), utils.ObstaclesToString(knownMap.KnownObstacles()))
 }
 
-func initComponents(startingPosition models.LocationDto, config *models.ConfigurationDto) (domains.IRoverDomain, error) {
+func printDownlinks(comms domains.ICommsDomain) {
+	for downlink := range comms.Downlinks() {
+		switch downlink.Type {
+		case models.DownlinkTypeAck:
+			fmt.Printf("\n\tBatch #%d received by the rover\n", downlink.BatchId)
+		case models.DownlinkTypeTelemetry:
+			if downlink.Error != "" {
+				fmt.Printf("\n\tBatch #%d ERROR - %s\n", downlink.BatchId, downlink.Error)
+			}
+			fmt.Printf("\n\tBatch #%d location: %s\n", downlink.BatchId, utils.LocationToString(downlink.Location))
+		}
+	}
+}
+
+func initComponents(startingPosition models.LocationDto, config *models.ConfigurationDto, clock domains.IClockDomain) (*components, error) {
 	gridDomain := domains.NewGridDomain(config.Grid)
-	obstacleDomain := domains.NewObstacleDomain(config.Obstacle)
+	obstacleMap := domains.NewObstacleDomain(config.Obstacle)
+	obstacleDomains := []domains.IObstacleDomain{obstacleMap}
+	if len(config.MovingObstacles.Obstacles) > 0 {
+		obstacleDomains = append(obstacleDomains, domains.NewMovingObstacleDomain(clock, gridDomain, config.MovingObstacles))
+	}
+	if config.Terrain.HeightmapPath != "" {
+		heightmap, err := utils.LoadHeightmap(config.Terrain.HeightmapPath, config.Terrain.HeightScaleMeters)
+		if err != nil {
+			return nil, err
+		}
+		terrain, err := domains.NewTerrainDomain(config.Grid, heightmap, config.Terrain)
+		if err != nil {
+			return nil, err
+		}
+		obstacleDomains = append(obstacleDomains, terrain)
+	}
+	var obstacleDomain domains.IObstacleDomain = obstacleMap
+	if len(obstacleDomains) > 1 {
+		obstacleDomain = domains.NewCompositeObstacleDomain(obstacleDomains...)
+	}
+	eventStore := domains.NewEventStoreDomain(config.SnapshotInterval)
+	eventBus := domains.NewEventBusDomain()
 
-	rover, err := domains.NewRoverDomain(startingPosition, gridDomain, obstacleDomain)
+	rover, err := domains.NewRoverDomain(roverId, startingPosition, gridDomain, obstacleDomain, eventStore, eventBus, clock)
+	if err != nil {
+		return nil, err
+	}
+
+	// The known map follows the rover events of the event store, synced by
+	// the planners before planning so no event is missed
+	knownMap := domains.NewKnownMapDomain(gridDomain, obstacleDomain, config.Sensor, roverId, eventStore)
+	knownMap.Sync()
+
+	// In fog of war the planners only know the obstacles sensed so far, the
+	// rover still running into the ground truth ones
+	var plannerObstacles domains.IObstacleDomain = obstacleDomain
+	if config.Sensor.Range > 0 {
+		plannerObstacles = knownMap
+	}
+
+	components := &components{
+		rover:      rover,
+		eventStore: eventStore,
+		eventBus:   eventBus,
+		obstacles:  api.NewObstacleController(obstacleMap, gridDomain, rover, configPath, mapPath),
+		knownMap:   knownMap,
+		coverage:   domains.NewCoveragePlannerDomain(gridDomain, plannerObstacles),
+		mission:    domains.NewMissionPlannerDomain(gridDomain, plannerObstacles, config.Energy),
+		analysis:   domains.NewMapAnalysisDomain(config.Grid, obstacleDomain),
+		navigator:  domains.NewNavigatorDomain(rover, gridDomain, plannerObstacles, startingPosition, config.Retreat),
+	}
+	if config.Comms.LightTimeMilliseconds > 0 {
+		components.comms = domains.NewCommsDomain(rover, config.Comms, config.CommandDuration, clock)
+	}
+	if config.Security.Key != "" {
+		components.envelope = domains.NewEnvelopeDomain(config.Security)
+	}
 
-	return rover, err
+	return components, nil
 }
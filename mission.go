@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/utils"
+)
+
+// planMission orders the sites of a mission and prints the commands of each
+// leg, e.g. "mission -objective energy 3 4 8 1 9 9"
+func planMission(args []string, components *components) error {
+	flags := flag.NewFlagSet("mission", flag.ContinueOnError)
+	objective := flags.String("objective", string(models.MissionObjectiveCommands), "Minimized objective: commands or energy")
+	sitesPath := flags.String("sites", "", "JSON file listing the sites, e.g. [{\"XPoint\": 3, \"YPoint\": 4}]")
+	execute := flags.Bool("execute", false, "Executes the mission commands on the rover")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg()%2 != 0 || (flags.NArg() == 0 && *sitesPath == "") {
+		return fmt.Errorf("usage: mission [-objective commands|energy] [-sites <file>] [-execute] <x> <y>...")
+	}
+
+	sites := []models.PointDto{}
+	if *sitesPath != "" {
+		content, err := os.ReadFile(*sitesPath)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(content, &sites); err != nil {
+			return fmt.Errorf("sites file: %v", err)
+		}
+	}
+	for i := 0; i < flags.NArg(); i += 2 {
+		x, errX := strconv.Atoi(flags.Arg(i))
+		y, errY := strconv.Atoi(flags.Arg(i + 1))
+		if errX != nil || errY != nil {
+			return fmt.Errorf("site '%s %s' is not a point", flags.Arg(i), flags.Arg(i+1))
+		}
+		sites = append(sites, models.PointDto{XPoint: x, YPoint: y})
+	}
+
+	plan, err := components.mission.PlanMission(components.rover.Location(), sites, models.MissionObjective(*objective))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("leg\tsite\tcost\tcommands")
+	for i, leg := range plan.Legs {
+		fmt.Printf("%d\t%s\t%d\t%s\n", i+1, utils.PointToString(leg.Site), leg.Cost, strings.Join(leg.Commands, ""))
+	}
+	fmt.Printf("Total cost: %d (%s), commands: %d\n", plan.Cost, *objective, len(plan.Commands))
+	fmt.Printf("Final location: %s\n", utils.LocationToString(plan.Location))
+
+	if *execute {
+		location, err := components.rover.ExecuteCommands(plan.Commands)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rover location: %s\n", utils.LocationToString(location))
+	}
+
+	return nil
+}
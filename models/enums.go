@@ -7,6 +7,11 @@ const (
 	DirectionSouth Direction = "S"
 	DirectionEast  Direction = "E"
 	DirectionWest  Direction = "W"
+	// The diagonal directions are only headed on grids with diagonals
+	DirectionNorthEast Direction = "NE"
+	DirectionNorthWest Direction = "NW"
+	DirectionSouthEast Direction = "SE"
+	DirectionSouthWest Direction = "SW"
 )
 
 type Command string
@@ -24,3 +29,93 @@ const (
 	MoveTypeForward  MoveType = 1
 	MoveTypeBackward MoveType = -1
 )
+
+type EventType string
+
+const (
+	EventTypeLanded              EventType = "Landed"
+	EventTypeMoved               EventType = "Moved"
+	EventTypeTurned              EventType = "Turned"
+	EventTypeObstacleEncountered EventType = "ObstacleEncountered"
+	EventTypeEdgeWrapped         EventType = "EdgeWrapped"
+	EventTypeBatchAborted        EventType = "BatchAborted"
+	EventTypeBatchCompleted      EventType = "BatchCompleted"
+)
+
+type DeliveryPolicy string
+
+const (
+	// DeliveryPolicyDrop discards the events published while the subscriber buffer is full
+	DeliveryPolicyDrop DeliveryPolicy = "drop"
+	// DeliveryPolicyBlock blocks the publisher until the subscriber buffer has room
+	DeliveryPolicyBlock DeliveryPolicy = "block"
+)
+
+type TelemetryFrameType string
+
+const (
+	TelemetryFrameTypeState  TelemetryFrameType = "state"
+	TelemetryFrameTypeEvent  TelemetryFrameType = "event"
+	TelemetryFrameTypeResult TelemetryFrameType = "result"
+	TelemetryFrameTypeError  TelemetryFrameType = "error"
+)
+
+type DownlinkType string
+
+const (
+	// DownlinkTypeAck acknowledges the rover received a command batch
+	DownlinkTypeAck DownlinkType = "ack"
+	// DownlinkTypeTelemetry reports the rover location after executing a command batch
+	DownlinkTypeTelemetry DownlinkType = "telemetry"
+)
+
+type FrameType byte
+
+const (
+	// FrameTypeCommands carries a command batch to the rover
+	FrameTypeCommands FrameType = 1
+	// FrameTypeAck acknowledges a command batch received by the rover
+	FrameTypeAck FrameType = 2
+	// FrameTypeNack requests the retransmission of a command batch
+	FrameTypeNack FrameType = 3
+	// FrameTypeTelemetry reports the rover location after executing a command batch
+	FrameTypeTelemetry FrameType = 4
+)
+
+type Role string
+
+const (
+	// RoleViewer reads the rover state
+	RoleViewer Role = "viewer"
+	// RoleDriver reads the rover state and sends commands
+	RoleDriver Role = "driver"
+	// RoleAdmin drives and resets the rover and edits the grid and obstacles
+	RoleAdmin Role = "admin"
+)
+
+type Operation string
+
+const (
+	OperationReadState    Operation = "read-state"
+	OperationSendCommands Operation = "send-commands"
+	OperationEditMap      Operation = "edit-map"
+	OperationResetRover   Operation = "reset-rover"
+)
+
+type ExplorationStrategy string
+
+const (
+	// ExplorationStrategyNearest heads to the nearest unexplored point
+	ExplorationStrategyNearest ExplorationStrategy = "nearest"
+	// ExplorationStrategyRandom heads to a random reachable unexplored point
+	ExplorationStrategyRandom ExplorationStrategy = "random"
+)
+
+type MissionObjective string
+
+const (
+	// MissionObjectiveCommands minimizes the number of commands of the mission
+	MissionObjectiveCommands MissionObjective = "commands"
+	// MissionObjectiveEnergy minimizes the energy of the mission commands
+	MissionObjectiveEnergy MissionObjective = "energy"
+)
@@ -1,8 +1,18 @@
 package models
 
 type ConfigurationDto struct {
-	Grid     GridDto
-	Obstacle []ObstacleDto
+	Grid             GridDto
+	Obstacle         []ObstacleDto
+	SnapshotInterval int
+	Comms            CommsDto
+	Security         SecurityDto
+	CommandDuration  CommandDurationDto
+	MovingObstacles  MovingObstaclesDto
+	Sensor           SensorDto
+	Energy           EnergyDto
+	Retreat          RetreatDto
+	Terrain          TerrainDto
+	Geo              GeoReferenceDto
 }
 
 type PointDto struct {
@@ -18,8 +28,322 @@ type LocationDto struct {
 type GridDto struct {
 	XPointMax int
 	YPointMax int
+	Wrapping  bool
+	// Diagonals lets the rover head in eight directions, turning 45 degrees
+	Diagonals bool
 }
 
 type ObstacleDto struct {
 	Point PointDto
 }
+
+// MovingObstaclesDto are the obstacles moving over time, the seed drives the
+// random paths
+type MovingObstaclesDto struct {
+	Seed      int64
+	Obstacles []MovingObstacleDto
+}
+
+// MovingObstacleDto is an obstacle moving one step every step duration along
+// its path, or at random without a path
+type MovingObstacleDto struct {
+	Start PointDto
+	// Path is the sequence of points visited after the start, then the start
+	// again and so on
+	Path             []PointDto
+	StepMilliseconds int
+}
+
+type EventDto struct {
+	RoverId  int
+	Sequence int
+	Batch    int
+	Type     EventType
+	Command  string
+	// Location is the rover location after the event
+	Location LocationDto
+	// Point is the obstacle point of an ObstacleEncountered event
+	Point  PointDto
+	Reason string
+}
+
+type SnapshotDto struct {
+	RoverId  int
+	Sequence int
+	Batch    int
+	Location LocationDto
+}
+
+type TelemetryFrameDto struct {
+	Type     TelemetryFrameType
+	Location LocationDto
+	Event    *EventDto
+	Error    string
+}
+
+type CommandFrameDto struct {
+	Commands []string
+	Envelope *EnvelopeDto
+}
+
+type CommsDto struct {
+	// LightTimeMilliseconds is the one way delay between Earth and the rover
+	LightTimeMilliseconds int
+	// RetransmitTimeoutMilliseconds is the time waited for the telemetry of a
+	// command batch before sending it again. Defaults to twice the light time plus a margin.
+	RetransmitTimeoutMilliseconds int
+	Link                          LinkDto
+}
+
+type LinkDto struct {
+	DropProbability      float64
+	DuplicateProbability float64
+	ReorderProbability   float64
+	CorruptProbability   float64
+	Seed                 int64
+}
+
+type FrameDto struct {
+	Sequence uint32
+	Type     FrameType
+	Payload  []byte
+}
+
+type DownlinkDto struct {
+	BatchId  int
+	Type     DownlinkType
+	Location LocationDto
+	Error    string
+}
+
+type SecurityDto struct {
+	// Key is the shared key signing the command batches. Batches are not signed when empty.
+	Key string
+	// MaxAgeSeconds is the age after which a signed batch is rejected as stale
+	MaxAgeSeconds int
+}
+
+type EnvelopeDto struct {
+	Sequence uint64
+	// Timestamp is the signing time in Unix milliseconds
+	Timestamp int64
+	Commands  []string
+	// Signature is the hex encoded HMAC-SHA256 of the sequence, timestamp and commands
+	Signature string
+}
+
+type TokensDto struct {
+	Tokens []TokenDto
+}
+
+type TokenDto struct {
+	Token string
+	Role  Role
+	// Name identifies the token owner
+	Name string
+}
+
+type CommandResultDto struct {
+	Location LocationDto
+	// Error is set when the batch was aborted
+	Error string
+}
+
+type StateDto struct {
+	Location LocationDto
+}
+
+type StreamEventsRequestDto struct {
+	FromSequence int
+}
+
+type PlanRequestDto struct {
+	Commands []string
+}
+
+type PlanDto struct {
+	Location LocationDto
+	Events   []EventDto
+	// Error is set when the batch would be aborted
+	Error string
+}
+
+// CommandDurationDto is the simulated time each command takes
+type CommandDurationDto struct {
+	ForwardMilliseconds  int
+	BackwardMilliseconds int
+	LeftMilliseconds     int
+	RightMilliseconds    int
+}
+
+// SimulationResultDto is the outcome of a command batch run on the simulation clock
+type SimulationResultDto struct {
+	Batch                 int
+	Commands              []string
+	SubmittedMilliseconds int64
+	CompletedMilliseconds int64
+	Location              LocationDto
+	Error                 string
+}
+
+type ObstaclesDto struct {
+	Obstacles []ObstacleDto
+	// Error is set when the edit was rejected
+	Error string
+}
+
+// SensorDto is the rover obstacle sensor. With a range the obstacles are
+// hidden to the rover until sensed.
+type SensorDto struct {
+	// Range is the distance in points the sensor sees at, 0 disables the fog of war
+	Range int
+	// FieldOfViewDegrees is the angle seen around the rover direction,
+	// all around when 0
+	FieldOfViewDegrees int
+}
+
+// ExplorationStepDto is the map coverage after a command batch chosen by the explorer
+type ExplorationStepDto struct {
+	Step     int
+	Commands []string
+	Location LocationDto
+	// CommandsUsed is the number of commands sent since the exploration started
+	CommandsUsed   int
+	ExploredPoints int
+	// Coverage is the ratio of the grid points explored, from 0 to 1
+	Coverage float64
+	Error    string
+}
+
+// RegionDto is the rectangle between two corner points, included
+type RegionDto struct {
+	Min PointDto
+	Max PointDto
+}
+
+// CoveragePlanDto are the commands visiting every reachable point of a region
+type CoveragePlanDto struct {
+	Commands []string
+	// Location is the rover location at the end of the commands
+	Location LocationDto
+	// Cells is the number of cells the region is split into around the obstacles
+	Cells int
+	// Visits are the region points in the order they are visited
+	Visits []PointDto
+	// Unreachable are the region points free of obstacles the rover cannot reach
+	Unreachable []PointDto
+}
+
+// EnergyDto is the energy each command takes, 1 unit when not set
+type EnergyDto struct {
+	ForwardUnits  int
+	BackwardUnits int
+	LeftUnits     int
+	RightUnits    int
+}
+
+// MissionLegDto are the commands from the previous site to the site
+type MissionLegDto struct {
+	Site     PointDto
+	Commands []string
+	// Cost is the number of commands or the energy of the leg, after the objective
+	Cost int
+}
+
+// MissionPlanDto is the tour visiting the sites of a mission
+type MissionPlanDto struct {
+	// Order are the sites in the order they are visited
+	Order    []PointDto
+	Legs     []MissionLegDto
+	Commands []string
+	// Location is the rover location at the last site
+	Location LocationDto
+	Cost     int
+}
+
+// MapAnalysisDto reports the connectivity of the map from a start point
+type MapAnalysisDto struct {
+	Start      PointDto
+	FreePoints int
+	// ReachablePoints are the points reached from the start point, in its
+	// region or in the regions it drives down to
+	ReachablePoints int
+	// Regions are the areas free of obstacles where the points reach each
+	// other, the one holding the start point first
+	Regions []MapRegionDto
+}
+
+// MapRegionDto is an area free of obstacles where the points reach each
+// other, enclosed by obstacles, slopes too steep to climb or the grid edges
+type MapRegionDto struct {
+	Id        int
+	Reachable bool
+	Points    []PointDto
+	// ChokePoints are the points leaving points of the region unable to
+	// reach each other when blocked
+	ChokePoints []PointDto
+}
+
+// RetreatDto are the places the rover retreats to
+type RetreatDto struct {
+	// SafeZones are the points the rover retreats to, the nearest one first.
+	// The rover returns to its starting location when none is set.
+	SafeZones []PointDto
+	// Auto retreats the rover after an obstacle aborts a batch
+	Auto bool
+}
+
+// MapGenerationDto are the settings of a procedurally generated map
+type MapGenerationDto struct {
+	// Seed drives the generation, the same settings always give the same map
+	Seed int64
+	Grid GridDto
+	// Density is the ratio of the grid points that are obstacles, from 0 to 1.
+	// It is lower when obstacles are removed to connect the start and goal.
+	Density float64
+	// Clusters is the number of boulder fields, the obstacles are scattered
+	// over the grid when 0
+	Clusters int
+	// ClusterRadius is the spread of the boulder fields around their center
+	ClusterRadius int
+	// Start and Goal are kept free of obstacles and connected
+	Start PointDto
+	Goal  PointDto
+}
+
+// MapDto is a grid with its obstacles and the rover start location, Start
+// being nil when the map does not set it
+type MapDto struct {
+	Grid      GridDto
+	Obstacles []ObstacleDto
+	Start     *LocationDto
+}
+
+// TerrainDto derives direction-aware obstacles from the slopes of a heightmap
+type TerrainDto struct {
+	// HeightmapPath is a grayscale PNG or a CSV of elevations in meters, north
+	// up, with one value per grid point. No terrain when empty.
+	HeightmapPath string
+	// HeightScaleMeters is the elevation of the white PNG pixels, black being 0
+	HeightScaleMeters float64
+	// CellSizeMeters is the distance between two neighbour points, 1 when not set
+	CellSizeMeters float64
+	// MaxSlope is the steepest rise over run the rover climbs, it drives down
+	// any slope
+	MaxSlope float64
+}
+
+// HeightmapDto holds the elevation in meters of each point, Elevations[y][x]
+type HeightmapDto struct {
+	Elevations [][]float64
+}
+
+// GeoReferenceDto maps the grid points to planetary coordinates: x goes east
+// and y north from the origin point 0 0, one cell apart
+type GeoReferenceDto struct {
+	OriginLatitude  float64
+	OriginLongitude float64
+	CellSizeMeters  float64
+	// PlanetRadiusMeters is the Mars mean radius when not set
+	PlanetRadiusMeters float64
+}
@@ -1 +1 @@
-go.exe test -v -cover -timeout 30s -coverprofile=go-code-cover.out ./domains
\ No newline at end of file
+go.exe test -v -race -cover -timeout 30s -coverprofile=go-code-cover.out ./...
\ No newline at end of file
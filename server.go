@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/mars-rover-go/api"
+	"github.com/mars-rover-go/models"
+)
+
+// startServer serves the rover over HTTP:
+//   - /ws: websocket live telemetry and commands
+//   - /api: REST state, commands and configuration
+//   - /rover.v1.RoverService: RoverService methods, see proto/rover/v1/rover.proto
+//
+// When a tokens file is given every request needs a token whose role allows the operation.
+func startServer(args []string, components *components) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", ":8080", "HTTP listen address")
+	tokensPath := flags.String("tokens", "", "Tokens file, every client is allowed when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	authorizer, err := loadAuthorizer(*tokensPath)
+	if err != nil {
+		return err
+	}
+
+	controller := api.NewRoverController(components.rover, components.envelope, components.navigator)
+
+	mux := http.NewServeMux()
+	mux.Handle("/ws", api.NewTelemetryServer(controller, components.eventBus, authorizer))
+	mux.Handle("/api/", api.NewHttpHandler(controller, components.obstacles, configPath, authorizer))
+	mux.Handle(api.RpcPathPrefix, api.NewRpcHandler(roverId, controller, components.obstacles, components.eventStore, components.eventBus, authorizer))
+
+	fmt.Printf("Serving rover on %s\n", *addr)
+
+	return http.ListenAndServe(*addr, mux)
+}
+
+// startTcpServer serves the rover over the line based text protocol
+func startTcpServer(args []string, components *components) error {
+	flags := flag.NewFlagSet("tcp", flag.ContinueOnError)
+	addr := flags.String("addr", ":9000", "TCP listen address")
+	tokensPath := flags.String("tokens", "", "Tokens file, every client is allowed when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	authorizer, err := loadAuthorizer(*tokensPath)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Serving rover on %s\n", listener.Addr())
+
+	controller := api.NewRoverController(components.rover, components.envelope, components.navigator)
+	return api.NewTcpServer(controller, components.obstacles, authorizer).Serve(listener)
+}
+
+func loadAuthorizer(tokensPath string) (*api.Authorizer, error) {
+	if tokensPath == "" {
+		fmt.Println("WARNING - no tokens file, every client is allowed")
+		return nil, nil
+	}
+
+	return api.LoadAuthorizer(tokensPath)
+}
+
+// signCommands prints the command frame carrying the commands signed with
+// the configured key, e.g. "sign ffrl"
+func signCommands(args []string, components *components) error {
+	if components.envelope == nil {
+		return fmt.Errorf("security key not configured")
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("usage: sign <commands>")
+	}
+
+	envelope := components.envelope.Sign(strings.Split(args[0], ""))
+	commandFrame, err := json.Marshal(models.CommandFrameDto{Envelope: &envelope})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(commandFrame))
+
+	return nil
+}
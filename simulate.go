@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mars-rover-go/domains"
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/utils"
+)
+
+// simulationTick is the resolution of the simulation clock
+const simulationTick = time.Millisecond
+
+// simulateCommands runs the command batches on the simulation clock, with
+// the configured command durations, and prints when each one completes,
+// e.g. "simulate -abort 2500 ffrl bbl"
+func simulateCommands(args []string, components *components, clock *domains.SimulationClockDomain, durations models.CommandDurationDto) error {
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	abortAt := flags.Int("abort", 0, "Simulated time in milliseconds the batches are aborted at, never when 0")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("usage: simulate [-abort <milliseconds>] <commands>...")
+	}
+
+	simulation := domains.NewSimulationDomain(components.rover, clock, durations)
+	for _, commands := range flags.Args() {
+		simulation.Submit(strings.Split(commands, ""))
+	}
+
+	if *abortAt > 0 {
+		simulation.RunUntil(time.Duration(*abortAt) * time.Millisecond)
+		simulation.Abort()
+	}
+	end := simulation.Run()
+
+	for _, result := range simulation.Results() {
+		fmt.Printf("[%v] Batch #%d %s location: %s\n",
+			time.Duration(result.CompletedMilliseconds)*time.Millisecond,
+			result.Batch,
+			strings.Join(result.Commands, ""),
+			utils.LocationToString(result.Location))
+		if result.Error != "" {
+			fmt.Printf("\tERROR - %s\n", result.Error)
+		}
+	}
+	fmt.Printf("Simulation ended at %v\n", end)
+
+	return nil
+}
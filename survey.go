@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mars-rover-go/models"
+	"github.com/mars-rover-go/utils"
+)
+
+// surveyRegion plans the commands visiting every reachable point of a region
+// in a lawnmower pattern and prints them, e.g. "survey -execute 0 0 5 5"
+func surveyRegion(args []string, components *components) error {
+	flags := flag.NewFlagSet("survey", flag.ContinueOnError)
+	execute := flags.Bool("execute", false, "Executes the survey commands on the rover")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 4 {
+		return fmt.Errorf("usage: survey [-execute] <x1> <y1> <x2> <y2>")
+	}
+
+	coordinates := make([]int, 4)
+	for i, arg := range flags.Args() {
+		coordinate, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("coordinate '%s' is not a number", arg)
+		}
+		coordinates[i] = coordinate
+	}
+	region := models.RegionDto{
+		Min: models.PointDto{XPoint: coordinates[0], YPoint: coordinates[1]},
+		Max: models.PointDto{XPoint: coordinates[2], YPoint: coordinates[3]},
+	}
+
+	plan, err := components.coverage.PlanCoverage(components.rover.Location(), region)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Cells: %d, points visited: %d, commands: %d\n", plan.Cells, len(plan.Visits), len(plan.Commands))
+	fmt.Printf("Commands: %s\n", strings.Join(plan.Commands, ""))
+	fmt.Printf("Final location: %s\n", utils.LocationToString(plan.Location))
+	if len(plan.Unreachable) > 0 {
+		fmt.Printf("Unreachable points: %s\n", pointsToString(plan.Unreachable))
+	}
+
+	if *execute {
+		location, err := components.rover.ExecuteCommands(plan.Commands)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rover location: %s\n", utils.LocationToString(location))
+	}
+
+	return nil
+}
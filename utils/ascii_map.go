@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mars-rover-go/models"
+)
+
+// ASCII map cells, the rover start being its direction glyph
+const (
+	asciiMapFree     = '.'
+	asciiMapObstacle = '#'
+)
+
+// directionGlyphs are the one character glyphs of the directions, the
+// diagonals being laid out as on a numeric keypad
+var directionGlyphs = map[models.Direction]byte{
+	models.DirectionNorth:     'N',
+	models.DirectionSouth:     'S',
+	models.DirectionEast:      'E',
+	models.DirectionWest:      'W',
+	models.DirectionNorthEast: '9',
+	models.DirectionNorthWest: '7',
+	models.DirectionSouthEast: '3',
+	models.DirectionSouthWest: '1',
+}
+
+// DirectionGlyph returns the one character drawing the direction on the
+// maps, '?' for an unknown direction
+func DirectionGlyph(direction models.Direction) byte {
+	if glyph, ok := directionGlyphs[direction]; ok {
+		return glyph
+	}
+
+	return '?'
+}
+
+// ParseAsciiMap reads a map drawn with one character per point, north up:
+// '.' free, '#' obstacle and N, S, E or W the rover start with its direction,
+// or 9, 7, 3 or 1 for NE, NW, SE or SW.
+// The lines are the same length, blank lines are ignored.
+func ParseAsciiMap(r io.Reader) (models.MapDto, error) {
+	rows := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		row := strings.TrimRight(scanner.Text(), " \t\r")
+		if row == "" {
+			continue
+		}
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return models.MapDto{}, fmt.Errorf("map row %d has %d points, want %d", len(rows)+1, len(row), len(rows[0]))
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return models.MapDto{}, err
+	}
+	if len(rows) == 0 {
+		return models.MapDto{}, fmt.Errorf("map is empty")
+	}
+
+	m := models.MapDto{
+		Grid:      models.GridDto{XPointMax: len(rows[0]) - 1, YPointMax: len(rows) - 1},
+		Obstacles: []models.ObstacleDto{},
+	}
+
+	// The points are listed by x, then y, as the obstacles are sorted
+	for x := 0; x <= m.Grid.XPointMax; x++ {
+		for i := len(rows) - 1; i >= 0; i-- {
+			point := models.PointDto{XPoint: x, YPoint: len(rows) - 1 - i}
+
+			cell := rows[i][x]
+			switch cell {
+			case asciiMapFree:
+				continue
+			case asciiMapObstacle:
+				m.Obstacles = append(m.Obstacles, models.ObstacleDto{Point: point})
+				continue
+			}
+
+			direction, ok := glyphDirection(cell)
+			if !ok {
+				return models.MapDto{}, fmt.Errorf("map point %s is '%c', want '.', '#', N, S, E, W, 9, 7, 3 or 1", PointToString(point), cell)
+			}
+			if m.Start != nil {
+				return models.MapDto{}, fmt.Errorf("map has two rover starts, %s and %s", LocationToString(*m.Start), PointToString(point))
+			}
+			m.Start = &models.LocationDto{Point: point, Direction: direction}
+		}
+	}
+
+	return m, nil
+}
+
+// WriteAsciiMap writes the map in the format read by ParseAsciiMap
+func WriteAsciiMap(w io.Writer, m models.MapDto) error {
+	obstacles := map[models.PointDto]bool{}
+	for _, o := range m.Obstacles {
+		obstacles[o.Point] = true
+	}
+
+	writer := bufio.NewWriter(w)
+	for y := m.Grid.YPointMax; y >= 0; y-- {
+		for x := 0; x <= m.Grid.XPointMax; x++ {
+			point := models.PointDto{XPoint: x, YPoint: y}
+
+			switch {
+			case m.Start != nil && m.Start.Point == point:
+				glyph, ok := directionGlyphs[m.Start.Direction]
+				if !ok {
+					return fmt.Errorf("rover start direction '%s' unknown", m.Start.Direction)
+				}
+				writer.WriteByte(glyph)
+			case obstacles[point]:
+				writer.WriteByte(asciiMapObstacle)
+			default:
+				writer.WriteByte(asciiMapFree)
+			}
+		}
+		writer.WriteByte('\n')
+	}
+
+	return writer.Flush()
+}
+
+func glyphDirection(glyph byte) (models.Direction, bool) {
+	for direction, g := range directionGlyphs {
+		if g == glyph {
+			return direction, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestParseAsciiMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    models.MapDto
+		wantErr bool
+	}{
+		{
+			name: "Map with obstacles and start",
+			text: "..#.\n" +
+				"....\n" +
+				".E.#\n",
+			want: models.MapDto{
+				Grid: models.GridDto{XPointMax: 3, YPointMax: 2},
+				Obstacles: []models.ObstacleDto{
+					{Point: models.PointDto{XPoint: 2, YPoint: 2}},
+					{Point: models.PointDto{XPoint: 3, YPoint: 0}},
+				},
+				Start: &models.LocationDto{Point: models.PointDto{XPoint: 1, YPoint: 0}, Direction: models.DirectionEast},
+			},
+		},
+		{
+			name: "Map without start, blank lines and trailing spaces",
+			text: "\n#. \r\n..\n\n",
+			want: models.MapDto{
+				Grid:      models.GridDto{XPointMax: 1, YPointMax: 1},
+				Obstacles: []models.ObstacleDto{{Point: models.PointDto{XPoint: 0, YPoint: 1}}},
+			},
+		},
+		{
+			name: "Map with a diagonal start",
+			text: "7.\n.#\n",
+			want: models.MapDto{
+				Grid:      models.GridDto{XPointMax: 1, YPointMax: 1},
+				Obstacles: []models.ObstacleDto{{Point: models.PointDto{XPoint: 1, YPoint: 0}}},
+				Start:     &models.LocationDto{Point: models.PointDto{XPoint: 0, YPoint: 1}, Direction: models.DirectionNorthWest},
+			},
+		},
+		{
+			name:    "Rows of different lengths",
+			text:    "...\n..\n",
+			wantErr: true,
+		},
+		{
+			name:    "Two starts",
+			text:    "N.\n.S\n",
+			wantErr: true,
+		},
+		{
+			name:    "Point unknown",
+			text:    ".x\n..\n",
+			wantErr: true,
+		},
+		{
+			name:    "Empty map",
+			text:    "\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAsciiMap(strings.NewReader(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAsciiMap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAsciiMap() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteAsciiMap(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{
+			name: "Map with a start",
+			text: "..#.\n" +
+				"S...\n" +
+				"...#\n",
+		},
+		{
+			name: "Map with a diagonal start",
+			text: "..#.\n" +
+				".3..\n" +
+				"...#\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseAsciiMap(strings.NewReader(tt.text))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got bytes.Buffer
+			if err := WriteAsciiMap(&got, m); err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tt.text {
+				t.Errorf("WriteAsciiMap() = %q, want %q", got.String(), tt.text)
+			}
+		})
+	}
+}
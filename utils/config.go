@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/mars-rover-go/models"
+)
+
+func LoadConfiguration(path string) (*models.ConfigurationDto, error) {
+	var config models.ConfigurationDto
+
+	configValue, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(configValue, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+func SaveConfiguration(path string, config models.ConfigurationDto) error {
+	configValue, err := json.MarshalIndent(config, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, configValue, 0644)
+}
@@ -0,0 +1,203 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/mars-rover-go/models"
+)
+
+// MarsRadiusMeters is the Mars mean radius
+const MarsRadiusMeters = 3389500
+
+// PointToLatLon returns the latitude and longitude in degrees of the point
+// center, on an equirectangular projection around the origin
+func PointToLatLon(geo models.GeoReferenceDto, point models.PointDto) (float64, float64) {
+	x, y := float64(point.XPoint), float64(point.YPoint)
+
+	return geo.OriginLatitude + y*latitudeDegreesPerCell(geo), geo.OriginLongitude + x*longitudeDegreesPerCell(geo)
+}
+
+// latLonToGrid returns the grid coordinates of the latitude and longitude,
+// not rounded to a point
+func latLonToGrid(geo models.GeoReferenceDto, latitude float64, longitude float64) (float64, float64) {
+	return (longitude - geo.OriginLongitude) / longitudeDegreesPerCell(geo), (latitude - geo.OriginLatitude) / latitudeDegreesPerCell(geo)
+}
+
+func latitudeDegreesPerCell(geo models.GeoReferenceDto) float64 {
+	radius := geo.PlanetRadiusMeters
+	if radius <= 0 {
+		radius = MarsRadiusMeters
+	}
+
+	return geo.CellSizeMeters / radius * 180 / math.Pi
+}
+
+func longitudeDegreesPerCell(geo models.GeoReferenceDto) float64 {
+	return latitudeDegreesPerCell(geo) / math.Cos(geo.OriginLatitude*math.Pi/180)
+}
+
+// geoJson is the subset of GeoJSON objects read and written: feature
+// collections of features with a geometry
+type geoJson struct {
+	Type        string                 `json:"type"`
+	Features    []geoJson              `json:"features,omitempty"`
+	Geometry    *geoJson               `json:"geometry,omitempty"`
+	Properties  map[string]interface{} `json:"properties,omitempty"`
+	Coordinates json.RawMessage        `json:"coordinates,omitempty"`
+}
+
+// ImportGeoJsonObstacles returns the grid points whose center lies in the
+// Polygon and MultiPolygon geometries of the GeoJSON, the other geometries
+// being ignored
+func ImportGeoJsonObstacles(r io.Reader, geo models.GeoReferenceDto, grid models.GridDto) ([]models.ObstacleDto, error) {
+	if geo.CellSizeMeters <= 0 {
+		return nil, fmt.Errorf("geo reference cell size is not set")
+	}
+
+	var root geoJson
+	if err := json.NewDecoder(r).Decode(&root); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %v", err)
+	}
+
+	polygons := [][][][2]float64{}
+	var collect func(object geoJson) error
+	collect = func(object geoJson) error {
+		switch object.Type {
+		case "FeatureCollection":
+			for _, feature := range object.Features {
+				if err := collect(feature); err != nil {
+					return err
+				}
+			}
+		case "Feature":
+			if object.Geometry != nil {
+				return collect(*object.Geometry)
+			}
+		case "Polygon":
+			var polygon [][][2]float64
+			if err := json.Unmarshal(object.Coordinates, &polygon); err != nil {
+				return fmt.Errorf("invalid Polygon coordinates: %v", err)
+			}
+			polygons = append(polygons, polygon)
+		case "MultiPolygon":
+			var multiPolygon [][][][2]float64
+			if err := json.Unmarshal(object.Coordinates, &multiPolygon); err != nil {
+				return fmt.Errorf("invalid MultiPolygon coordinates: %v", err)
+			}
+			polygons = append(polygons, multiPolygon...)
+		}
+		return nil
+	}
+	if err := collect(root); err != nil {
+		return nil, err
+	}
+
+	obstacles := []models.ObstacleDto{}
+	for x := 0; x <= grid.XPointMax; x++ {
+		for y := 0; y <= grid.YPointMax; y++ {
+			for _, polygon := range polygons {
+				if inPolygon(geo, polygon, float64(x), float64(y)) {
+					obstacles = append(obstacles, models.ObstacleDto{Point: models.PointDto{XPoint: x, YPoint: y}})
+					break
+				}
+			}
+		}
+	}
+
+	return obstacles, nil
+}
+
+// inPolygon checks if the grid coordinates are in the polygon outer ring and
+// out of its holes, the rings being [longitude, latitude] positions
+func inPolygon(geo models.GeoReferenceDto, polygon [][][2]float64, x float64, y float64) bool {
+	inside := false
+	for _, ring := range polygon {
+		for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+			xi, yi := latLonToGrid(geo, ring[i][1], ring[i][0])
+			xj, yj := latLonToGrid(geo, ring[j][1], ring[j][0])
+			if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+				inside = !inside
+			}
+		}
+	}
+
+	return inside
+}
+
+// WriteGeoJsonTrajectory writes the rover locations of the events as a
+// GeoJSON feature collection of LineStrings, a new one starting each time
+// the rover lands or wraps to the opposite grid edge. The events recorded
+// without the EdgeWrapped ones start a new line when the rover moves more
+// than one point.
+func WriteGeoJsonTrajectory(w io.Writer, geo models.GeoReferenceDto, events []models.EventDto) error {
+	collection := geoJson{Type: "FeatureCollection", Features: []geoJson{}}
+	line := [][2]float64{}
+	first := models.EventDto{}
+
+	flush := func(last models.EventDto) error {
+		if len(line) < 2 {
+			return nil
+		}
+		coordinates, err := json.Marshal(line)
+		if err != nil {
+			return err
+		}
+		collection.Features = append(collection.Features, geoJson{
+			Type:     "Feature",
+			Geometry: &geoJson{Type: "LineString", Coordinates: coordinates},
+			Properties: map[string]interface{}{
+				"roverId":      first.RoverId,
+				"fromSequence": first.Sequence,
+				"toSequence":   last.Sequence,
+			},
+		})
+		return nil
+	}
+
+	previous := models.EventDto{}
+	for _, event := range events {
+		switch event.Type {
+		case models.EventTypeLanded, models.EventTypeEdgeWrapped:
+			if err := flush(previous); err != nil {
+				return err
+			}
+			line = [][2]float64{}
+			first = event
+		case models.EventTypeMoved:
+			if len(line) > 0 && !isNeighbourPoint(previous.Location.Point, event.Location.Point) {
+				if err := flush(previous); err != nil {
+					return err
+				}
+				line = [][2]float64{}
+			}
+			if len(line) == 0 {
+				first = event
+			}
+		default:
+			continue
+		}
+
+		latitude, longitude := PointToLatLon(geo, event.Location.Point)
+		line = append(line, [2]float64{longitude, latitude})
+		previous = event
+	}
+	if err := flush(previous); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(collection)
+}
+
+// isNeighbourPoint checks if the points are at most one point apart, the
+// diagonal ones included
+func isNeighbourPoint(a models.PointDto, b models.PointDto) bool {
+	dx, dy := a.XPoint-b.XPoint, a.YPoint-b.YPoint
+
+	return dx >= -1 && dx <= 1 && dy >= -1 && dy <= 1
+}
@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mars-rover-go/models"
+)
+
+// geoReferenceMocked maps one cell to one degree at the equator
+var geoReferenceMocked = models.GeoReferenceDto{CellSizeMeters: 1, PlanetRadiusMeters: 180 / math.Pi}
+
+func TestPointToLatLon(t *testing.T) {
+	tests := []struct {
+		name          string
+		geo           models.GeoReferenceDto
+		point         models.PointDto
+		wantLatitude  float64
+		wantLongitude float64
+	}{
+		{
+			name:          "Origin",
+			geo:           models.GeoReferenceDto{OriginLatitude: 18.4, OriginLongitude: 77.5, CellSizeMeters: 1},
+			point:         models.PointDto{XPoint: 0, YPoint: 0},
+			wantLatitude:  18.4,
+			wantLongitude: 77.5,
+		},
+		{
+			name:          "East and north of the origin",
+			geo:           geoReferenceMocked,
+			point:         models.PointDto{XPoint: 2, YPoint: 3},
+			wantLatitude:  3,
+			wantLongitude: 2,
+		},
+		{
+			name:          "Longitude degrees longer away from the equator",
+			geo:           models.GeoReferenceDto{OriginLatitude: 60, CellSizeMeters: 1, PlanetRadiusMeters: 180 / math.Pi},
+			point:         models.PointDto{XPoint: 1, YPoint: 1},
+			wantLatitude:  61,
+			wantLongitude: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latitude, longitude := PointToLatLon(tt.geo, tt.point)
+			if math.Abs(latitude-tt.wantLatitude) > 1e-9 || math.Abs(longitude-tt.wantLongitude) > 1e-9 {
+				t.Errorf("PointToLatLon() = %v, %v, want %v, %v", latitude, longitude, tt.wantLatitude, tt.wantLongitude)
+			}
+		})
+	}
+}
+
+func TestImportGeoJsonObstacles(t *testing.T) {
+	grid := models.GridDto{XPointMax: 4, YPointMax: 4}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    []models.PointDto
+		wantErr bool
+	}{
+		{
+			name: "Polygon",
+			text: `{"type": "FeatureCollection", "features": [{"type": "Feature", "geometry": {"type": "Polygon",
+				"coordinates": [[[0.5, 0.5], [2.5, 0.5], [2.5, 1.5], [0.5, 1.5], [0.5, 0.5]]]}}]}`,
+			want: []models.PointDto{{XPoint: 1, YPoint: 1}, {XPoint: 2, YPoint: 1}},
+		},
+		{
+			name: "Polygon with a hole",
+			text: `{"type": "Polygon", "coordinates": [
+				[[-0.5, -0.5], [2.5, -0.5], [2.5, 2.5], [-0.5, 2.5], [-0.5, -0.5]],
+				[[0.5, 0.5], [1.5, 0.5], [1.5, 1.5], [0.5, 1.5], [0.5, 0.5]]]}`,
+			want: []models.PointDto{
+				{XPoint: 0, YPoint: 0}, {XPoint: 0, YPoint: 1}, {XPoint: 0, YPoint: 2},
+				{XPoint: 1, YPoint: 0}, {XPoint: 1, YPoint: 2},
+				{XPoint: 2, YPoint: 0}, {XPoint: 2, YPoint: 1}, {XPoint: 2, YPoint: 2},
+			},
+		},
+		{
+			name: "MultiPolygon out and in the grid",
+			text: `{"type": "Feature", "geometry": {"type": "MultiPolygon", "coordinates": [
+				[[[10, 10], [11, 10], [11, 11], [10, 10]]],
+				[[[3.5, 3.5], [4.5, 3.5], [4.5, 4.5], [3.5, 4.5], [3.5, 3.5]]]]}}`,
+			want: []models.PointDto{{XPoint: 4, YPoint: 4}},
+		},
+		{
+			name: "Other geometries ignored",
+			text: `{"type": "Point", "coordinates": [1, 1]}`,
+			want: []models.PointDto{},
+		},
+		{
+			name:    "Invalid coordinates",
+			text:    `{"type": "Polygon", "coordinates": [1, 1]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obstacles, err := ImportGeoJsonObstacles(strings.NewReader(tt.text), geoReferenceMocked, grid)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ImportGeoJsonObstacles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := []models.PointDto{}
+			for _, obstacle := range obstacles {
+				got = append(got, obstacle.Point)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ImportGeoJsonObstacles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteGeoJsonTrajectory(t *testing.T) {
+	location := func(x int, y int) models.LocationDto {
+		return models.LocationDto{Point: models.PointDto{XPoint: x, YPoint: y}, Direction: models.DirectionNorth}
+	}
+
+	tests := []struct {
+		name            string
+		events          []models.EventDto
+		wantCoordinates [][][2]float64
+		wantSequences   [][2]float64
+	}{
+		{
+			name: "Wrapped to the opposite edge",
+			events: []models.EventDto{
+				{RoverId: 1, Sequence: 1, Type: models.EventTypeLanded, Location: location(0, 3)},
+				{RoverId: 1, Sequence: 2, Type: models.EventTypeMoved, Location: location(0, 4)},
+				{RoverId: 1, Sequence: 3, Type: models.EventTypeTurned, Location: location(0, 4)},
+				{RoverId: 1, Sequence: 4, Type: models.EventTypeEdgeWrapped, Location: location(0, 0)},
+				{RoverId: 1, Sequence: 5, Type: models.EventTypeMoved, Location: location(0, 1)},
+				{RoverId: 1, Sequence: 6, Type: models.EventTypeObstacleEncountered, Location: location(0, 1)},
+			},
+			wantCoordinates: [][][2]float64{{{0, 3}, {0, 4}}, {{0, 0}, {0, 1}}},
+			wantSequences:   [][2]float64{{1, 2}, {4, 5}},
+		},
+		{
+			name: "Wrapped without the EdgeWrapped event",
+			events: []models.EventDto{
+				{RoverId: 1, Sequence: 1, Type: models.EventTypeLanded, Location: location(0, 3)},
+				{RoverId: 1, Sequence: 2, Type: models.EventTypeMoved, Location: location(0, 4)},
+				{RoverId: 1, Sequence: 3, Type: models.EventTypeMoved, Location: location(0, 0)},
+				{RoverId: 1, Sequence: 4, Type: models.EventTypeMoved, Location: location(1, 1)},
+			},
+			wantCoordinates: [][][2]float64{{{0, 3}, {0, 4}}, {{0, 0}, {1, 1}}},
+			wantSequences:   [][2]float64{{1, 2}, {3, 4}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := WriteGeoJsonTrajectory(&b, geoReferenceMocked, tt.events); err != nil {
+				t.Fatal(err)
+			}
+
+			var got struct {
+				Type     string
+				Features []struct {
+					Geometry struct {
+						Type        string
+						Coordinates [][2]float64
+					}
+					Properties map[string]float64
+				}
+			}
+			if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Type != "FeatureCollection" || len(got.Features) != len(tt.wantCoordinates) {
+				t.Fatalf("WriteGeoJsonTrajectory() = %s, want a FeatureCollection of %d lines", b.String(), len(tt.wantCoordinates))
+			}
+			for i, feature := range got.Features {
+				if feature.Geometry.Type != "LineString" || !reflect.DeepEqual(feature.Geometry.Coordinates, tt.wantCoordinates[i]) {
+					t.Errorf("WriteGeoJsonTrajectory() line %d = %v, want %v", i, feature.Geometry.Coordinates, tt.wantCoordinates[i])
+				}
+				if sequences := [2]float64{feature.Properties["fromSequence"], feature.Properties["toSequence"]}; sequences != tt.wantSequences[i] {
+					t.Errorf("WriteGeoJsonTrajectory() line %d sequences = %v, want %v", i, sequences, tt.wantSequences[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mars-rover-go/models"
+)
+
+// LoadHeightmap reads a heightmap from a grayscale PNG, scaled from 0 for
+// black to heightScale meters for white, or from a CSV of elevations in meters
+func LoadHeightmap(path string, heightScale float64) (models.HeightmapDto, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return models.HeightmapDto{}, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		return DecodeHeightmapPng(file, heightScale)
+	case ".csv":
+		return ParseHeightmapCsv(file)
+	default:
+		return models.HeightmapDto{}, fmt.Errorf("heightmap '%s' is not a PNG or CSV file", path)
+	}
+}
+
+// DecodeHeightmapPng reads the elevations from the gray level of the PNG
+// pixels, the top row being north
+func DecodeHeightmapPng(r io.Reader, heightScale float64) (models.HeightmapDto, error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return models.HeightmapDto{}, err
+	}
+
+	bounds := img.Bounds()
+	heightmap := models.HeightmapDto{Elevations: make([][]float64, bounds.Dy())}
+	for row := 0; row < bounds.Dy(); row++ {
+		elevations := make([]float64, bounds.Dx())
+		for col := 0; col < bounds.Dx(); col++ {
+			gray := color.Gray16Model.Convert(img.At(bounds.Min.X+col, bounds.Min.Y+row)).(color.Gray16)
+			elevations[col] = float64(gray.Y) / math.MaxUint16 * heightScale
+		}
+		heightmap.Elevations[bounds.Dy()-1-row] = elevations
+	}
+
+	return heightmap, nil
+}
+
+// ParseHeightmapCsv reads the elevations in meters from comma separated
+// rows of the same length, the first row being north
+func ParseHeightmapCsv(r io.Reader) (models.HeightmapDto, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return models.HeightmapDto{}, err
+	}
+	if len(records) == 0 {
+		return models.HeightmapDto{}, fmt.Errorf("heightmap is empty")
+	}
+
+	heightmap := models.HeightmapDto{Elevations: make([][]float64, len(records))}
+	for row, record := range records {
+		elevations := make([]float64, len(record))
+		for col, field := range record {
+			elevation, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return models.HeightmapDto{}, fmt.Errorf("heightmap row %d column %d: %v", row+1, col+1, err)
+			}
+			elevations[col] = elevation
+		}
+		heightmap.Elevations[len(records)-1-row] = elevations
+	}
+
+	return heightmap, nil
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mars-rover-go/models"
+)
+
+func TestParseHeightmapCsv(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    models.HeightmapDto
+		wantErr bool
+	}{
+		{
+			name: "Elevations north up",
+			text: "3, 4.5, 5\n0, 1, 2\n",
+			want: models.HeightmapDto{Elevations: [][]float64{{0, 1, 2}, {3, 4.5, 5}}},
+		},
+		{
+			name:    "Rows of different lengths",
+			text:    "1,2,3\n1,2\n",
+			wantErr: true,
+		},
+		{
+			name:    "Elevation not a number",
+			text:    "1,high\n",
+			wantErr: true,
+		},
+		{
+			name:    "Empty heightmap",
+			text:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHeightmapCsv(strings.NewReader(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHeightmapCsv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseHeightmapCsv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeHeightmapPng(t *testing.T) {
+	// Black top left, white bottom right
+	img := image.NewGray(image.Rect(0, 0, 2, 2))
+	img.SetGray(0, 0, color.Gray{Y: 0})
+	img.SetGray(1, 0, color.Gray{Y: 51})
+	img.SetGray(0, 1, color.Gray{Y: 102})
+	img.SetGray(1, 1, color.Gray{Y: 255})
+
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, img); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := DecodeHeightmapPng(&encoded, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.HeightmapDto{Elevations: [][]float64{{40, 100}, {0, 20}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeHeightmapPng() = %v, want %v", got, want)
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/mars-rover-go/models"
 )
@@ -9,3 +10,29 @@ import (
 func LocationToString(location models.LocationDto) string {
 	return fmt.Sprintf("(%d,%d) %s", location.Point.XPoint, location.Point.YPoint, location.Direction)
 }
+
+func EventToString(event models.EventDto) string {
+	description := fmt.Sprintf("#%d batch %d %s: %s", event.Sequence, event.Batch, event.Type, LocationToString(event.Location))
+
+	switch event.Type {
+	case models.EventTypeObstacleEncountered:
+		description += fmt.Sprintf(" obstacle %s", PointToString(event.Point))
+	case models.EventTypeBatchAborted:
+		description += fmt.Sprintf(" - %s", event.Reason)
+	}
+
+	return description
+}
+
+func PointToString(point models.PointDto) string {
+	return fmt.Sprintf("(%d,%d)", point.XPoint, point.YPoint)
+}
+
+func ObstaclesToString(obstacles []models.ObstacleDto) string {
+	points := make([]string, len(obstacles))
+	for i, o := range obstacles {
+		points[i] = PointToString(o.Point)
+	}
+
+	return strings.Join(points, " ")
+}
@@ -0,0 +1,311 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestConn_WriteMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		isClient   bool
+		length     int
+		wantHeader []byte
+	}{
+		{
+			name:       "Server short message",
+			length:     5,
+			wantHeader: []byte{0x81, 5},
+		},
+		{
+			name:       "Server 16-bit length",
+			length:     200,
+			wantHeader: []byte{0x81, 126, 0x00, 0xC8},
+		},
+		{
+			name:       "Server 64-bit length",
+			length:     70000,
+			wantHeader: []byte{0x81, 127, 0, 0, 0, 0, 0, 0x01, 0x11, 0x70},
+		},
+		{
+			name:       "Client masked short message",
+			isClient:   true,
+			length:     5,
+			wantHeader: []byte{0x81, 0x80 | 5},
+		},
+		{
+			name:       "Client masked 16-bit length",
+			isClient:   true,
+			length:     0xFFFF,
+			wantHeader: []byte{0x81, 0x80 | 126, 0xFF, 0xFF},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, peer := newConnMocked(t, tt.isClient)
+			message := bytes.Repeat([]byte("rover"), tt.length/5+1)[:tt.length]
+
+			errs := make(chan error, 1)
+			go func() {
+				errs <- c.WriteMessage(OpText, message)
+			}()
+
+			header := make([]byte, len(tt.wantHeader))
+			if _, err := io.ReadFull(peer, header); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(header, tt.wantHeader) {
+				t.Errorf("Conn.WriteMessage() header = %x, want %x", header, tt.wantHeader)
+			}
+
+			mask := make([]byte, 4)
+			if tt.isClient {
+				if _, err := io.ReadFull(peer, mask); err != nil {
+					t.Fatal(err)
+				}
+			}
+			payload := make([]byte, tt.length)
+			if _, err := io.ReadFull(peer, payload); err != nil {
+				t.Fatal(err)
+			}
+			for i := range payload {
+				payload[i] ^= mask[i%4]
+			}
+			if !bytes.Equal(payload, message) {
+				t.Errorf("Conn.WriteMessage() payload differs from the message")
+			}
+			if err := <-errs; err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestConn_ReadMessage(t *testing.T) {
+	extended := make([]byte, 65536)
+	extendedFrame := append([]byte{0x82, 127, 0, 0, 0, 0, 0, 0x01, 0x00, 0x00}, extended...)
+
+	tests := []struct {
+		name     string
+		isClient bool
+		frames   []byte
+		wantOp   int
+		want     []byte
+		// wantReply is the frame the connection answers with, unmasked
+		wantReply []byte
+		wantErr   bool
+	}{
+		{
+			name:   "Masked text from the client",
+			frames: []byte{0x81, 0x85, 0x37, 0xfa, 0x21, 0x3d, 0x7f, 0x9f, 0x4d, 0x51, 0x58},
+			wantOp: OpText,
+			want:   []byte("Hello"),
+		},
+		{
+			name:     "Unmasked fragmented text from the server",
+			isClient: true,
+			frames:   []byte{0x01, 0x03, 'H', 'e', 'l', 0x80, 0x02, 'l', 'o'},
+			wantOp:   OpText,
+			want:     []byte("Hello"),
+		},
+		{
+			name:      "Ping between fragments answered by a pong",
+			isClient:  true,
+			frames:    []byte{0x01, 0x03, 'H', 'e', 'l', 0x89, 0x02, 'h', 'i', 0x80, 0x02, 'l', 'o'},
+			wantOp:    OpText,
+			want:      []byte("Hello"),
+			wantReply: []byte{0x8A, 0x80 | 2, 'h', 'i'},
+		},
+		{
+			name:     "Pong ignored",
+			isClient: true,
+			frames:   []byte{0x8A, 0x00, 0x81, 0x01, 'f'},
+			wantOp:   OpText,
+			want:     []byte("f"),
+		},
+		{
+			name:     "Binary with a 64-bit length",
+			isClient: true,
+			frames:   extendedFrame,
+			wantOp:   OpBinary,
+			want:     extended,
+		},
+		{
+			name:    "Unmasked frame from the client",
+			frames:  []byte{0x81, 0x01, 'f'},
+			wantErr: true,
+		},
+		{
+			name:     "Continuation without a message",
+			isClient: true,
+			frames:   []byte{0x80, 0x01, 'f'},
+			wantErr:  true,
+		},
+		{
+			name:     "Frame too large",
+			isClient: true,
+			frames:   []byte{0x82, 127, 0, 0, 0, 0, 0x01, 0, 0, 0},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, peer := newConnMocked(t, tt.isClient)
+			go func() {
+				_, _ = peer.Write(tt.frames)
+			}()
+
+			op, got, err := c.ReadMessage()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Conn.ReadMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if op != tt.wantOp || !bytes.Equal(got, tt.want) {
+				t.Errorf("Conn.ReadMessage() = %d %q, want %d %q", op, truncated(got), tt.wantOp, truncated(tt.want))
+			}
+
+			if tt.wantReply != nil {
+				if reply := readUnmasked(t, peer); !bytes.Equal(reply, tt.wantReply) {
+					t.Errorf("Conn.ReadMessage() reply = %x, want %x", reply, tt.wantReply)
+				}
+			}
+		})
+	}
+}
+
+func TestConn_ReadMessageClose(t *testing.T) {
+	c, peer := newConnMocked(t, true)
+	go func() {
+		_, _ = peer.Write([]byte{0x88, 0x02, 0x03, 0xE8})
+	}()
+
+	if _, _, err := c.ReadMessage(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Conn.ReadMessage() error = %v, want ErrClosed", err)
+	}
+
+	// The close frame is echoed, then the writes fail with ErrClosed
+	if reply, want := readUnmasked(t, peer), []byte{0x88, 0x80 | 2, 0x03, 0xE8}; !bytes.Equal(reply, want) {
+		t.Errorf("Conn.ReadMessage() reply = %x, want %x", reply, want)
+	}
+	if err := c.WriteMessage(OpText, []byte("f")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Conn.WriteMessage() error = %v, want ErrClosed", err)
+	}
+}
+
+func TestDial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			op, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(op, message); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	conn, err := Dial("ws" + strings.TrimPrefix(server.URL, "http") + "/ws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	for _, length := range []int{0, 125, 126, 0xFFFF, 0x10000} {
+		message := bytes.Repeat([]byte{'f'}, length)
+		if err := conn.WriteMessage(OpBinary, message); err != nil {
+			t.Fatal(err)
+		}
+		op, got, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op != OpBinary || !bytes.Equal(got, message) {
+			t.Errorf("Dial() echo of %d bytes = %d, %d bytes", length, op, len(got))
+		}
+	}
+
+	if _, err := Dial("http" + strings.TrimPrefix(server.URL, "http")); err == nil {
+		t.Errorf("Dial() error = nil, want scheme not supported")
+	}
+}
+
+// newConnMocked returns a connection on one end of a loopback TCP
+// connection and the raw other end
+func newConnMocked(t *testing.T, isClient bool) (*Conn, net.Conn) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			close(accepted)
+			return
+		}
+		accepted <- conn
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	peer, ok := <-accepted
+	if !ok {
+		t.Fatal("accept failed")
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		peer.Close()
+	})
+
+	return &Conn{conn: conn, reader: bufio.NewReader(conn), isClient: isClient}, peer
+}
+
+// readUnmasked reads a short frame sent by a client and returns it unmasked,
+// without its mask
+func readUnmasked(t *testing.T, peer net.Conn) []byte {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(peer, header); err != nil {
+		t.Fatal(err)
+	}
+	mask := make([]byte, 4)
+	if _, err := io.ReadFull(peer, mask); err != nil {
+		t.Fatal(err)
+	}
+	payload := make([]byte, header[1]&0x7F)
+	if _, err := io.ReadFull(peer, payload); err != nil {
+		t.Fatal(err)
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return append(header, payload...)
+}
+
+func truncated(message []byte) []byte {
+	if len(message) > 16 {
+		return message[:16]
+	}
+	return message
+}